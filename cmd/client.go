@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"fluidnc-client/pkg/fluidnc"
+)
+
+// traceTimeFormat records trace timestamps to the microsecond
+const traceTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+var (
+	traceOnce   sync.Once
+	traceLogger *slog.Logger // Shared by every client so fleet traces interleave in one file
+)
+
+// newClient creates a client that logs to stderr at the configured level and,
+// when a trace file is configured, records its protocol traffic there
+func newClient(cfg *fluidnc.Config) *fluidnc.Client {
+	logger := newLogger(cfg)
+
+	trace := openTrace(cfg, logger)
+	if trace != nil {
+		if cfg.Machine != "" {
+			trace = trace.With("machine", cfg.Machine)
+		} else {
+			trace = trace.With("host", cfg.Host)
+		}
+	}
+
+	return fluidnc.NewClientWithOptions(&fluidnc.ClientOptions{
+		Config: cfg,
+		Logger: logger,
+		Trace:  trace,
+	})
+}
+
+// newLogger creates the stderr logger for log_level and log_format, where
+// --verbose lowers the level to debug
+func newLogger(cfg *fluidnc.Config) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		level = slog.LevelWarn
+	}
+	if cfg.Verbose {
+		level = slog.LevelDebug
+	}
+
+	return slog.New(newLogHandler(os.Stderr, cfg.LogFormat, level, ""))
+}
+
+// newLogHandler creates a text or JSON handler, formatting times with
+// timeFormat when it is set
+func newLogHandler(w io.Writer, format string, level slog.Level, timeFormat string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if timeFormat != "" {
+		opts.ReplaceAttr = func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				attr.Value = slog.StringValue(attr.Value.Time().Format(timeFormat))
+			}
+			return attr
+		}
+	}
+
+	if strings.EqualFold(format, "json") {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// openTrace opens the trace file on first use, appending to it so repeated
+// runs build one history. Failing to open it is logged and tracing stays off.
+func openTrace(cfg *fluidnc.Config, logger *slog.Logger) *slog.Logger {
+	if cfg.TraceFile == "" {
+		return nil
+	}
+
+	traceOnce.Do(func() {
+		file, err := os.OpenFile(cfg.TraceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			logger.Error("protocol trace disabled", "error", err)
+			return
+		}
+
+		traceLogger = slog.New(newLogHandler(file, cfg.LogFormat, slog.LevelDebug, traceTimeFormat))
+		traceLogger.Info("trace started", "args", strings.Join(os.Args[1:], " "))
+	})
+
+	return traceLogger
+}
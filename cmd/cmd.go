@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
 	"github.com/spf13/cobra"
 )
 
@@ -14,23 +13,26 @@ var cmdCmd = &cobra.Command{
 	Long:  "Send a single command to FluidNC via WebSocket connection.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
-		if err := client.Connect(); err != nil {
+		client := newClient(cfg)
+		if err := client.Connect(ctx); err != nil {
 			return err
 		}
 		defer client.Disconnect()
 
-		response, err := client.SendCommand(args[0])
+		response, err := client.SendCommand(ctx, args[0])
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(response)
+		newPrinter(cfg).Result(map[string]string{"command": args[0], "response": response}, nil, func() {
+			fmt.Println(response)
+		})
 		return nil
 	},
 }
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"fluidnc-client/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and create the configuration",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for errors",
+	Long:  "Check the global settings and every machine profile, reporting unknown keys and invalid values.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := config.ValidateAll()
+		if err != nil {
+			return err
+		}
+
+		// Read the format directly since the config may be too broken to load
+		out := printerFor(viper.GetString("output_format"), viper.GetString("output_template"))
+		out.Result(results, nil, func() {
+			if file := config.ConfigFile(); file != "" {
+				fmt.Printf("Config file: %s\n", file)
+			} else {
+				fmt.Println("No config file found, using defaults")
+			}
+			for _, result := range results {
+				if len(result.Problems) == 0 {
+					fmt.Printf("✓ %s\n", result.Target)
+					continue
+				}
+				fmt.Printf("✗ %s\n", result.Target)
+				for _, problem := range result.Problems {
+					fmt.Printf("    - %s\n", problem)
+				}
+			}
+		})
+
+		invalid := 0
+		for _, result := range results {
+			if len(result.Problems) > 0 {
+				invalid++
+			}
+		}
+		if invalid > 0 {
+			return fmt.Errorf("configuration has problems in %d of %d sections", invalid, len(results))
+		}
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long:  "Show the effective value of every setting after merging defaults, the config file, the selected machine, environment variables and flags, and where each value came from.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := config.Settings()
+		if err != nil {
+			return err
+		}
+
+		out := printerFor(viper.GetString("output_format"), viper.GetString("output_template"))
+		out.Result(settings, nil, func() {
+			if file := config.ConfigFile(); file != "" {
+				fmt.Printf("Config file: %s\n", file)
+			}
+			fmt.Printf("%-18s %-28s %s\n", "Setting", "Value", "Source")
+			fmt.Println(strings.Repeat("-", 70))
+			for _, setting := range settings {
+				fmt.Printf("%-18s %-28v %s\n", setting.Key, setting.Value, setting.Source)
+			}
+		})
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init [path]",
+	Short: "Write a starter config file",
+	Long:  "Write a commented starter config file to path, ./fluidnc-cli.yaml by default or ~/.fluidnc-cli/fluidnc-cli.yaml with --user.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		user, _ := cmd.Flags().GetBool("user")
+		force, _ := cmd.Flags().GetBool("force")
+		host, _ := cmd.Flags().GetString("host")
+		if host == "" {
+			host = "192.168.1.100"
+		}
+
+		path := "fluidnc-cli.yaml"
+		switch {
+		case len(args) > 0:
+			path = args[0]
+		case user:
+			userPath, err := config.UserConfigPath()
+			if err != nil {
+				return err
+			}
+			path = userPath
+		}
+
+		if err := config.WriteStarterConfig(path, host, force); err != nil {
+			return err
+		}
+
+		printerFor(viper.GetString("output_format"), viper.GetString("output_template")).Result(map[string]string{"wrote": path}, nil, func() {
+			fmt.Printf("Wrote %s\n", path)
+		})
+		return nil
+	},
+}
+
+func init() {
+	configInitCmd.Flags().Bool("user", false, "Write to ~/.fluidnc-cli/fluidnc-cli.yaml")
+	configInitCmd.Flags().Bool("force", false, "Overwrite an existing file")
+
+	configCmd.AddCommand(configValidateCmd, configShowCmd, configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
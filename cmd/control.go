@@ -1,27 +1,26 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
 )
 
 var controlCmd = &cobra.Command{
 	Use:   "control",
 	Short: "Machine control commands",
+	Long:  "Machine control commands. With --machines or --all the command is sent to each machine in parallel.",
 }
 
 var holdCmd = &cobra.Command{
 	Use:   "hold",
 	Short: "Send feed hold",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.Connect(); err != nil {
-			return err
-		}
-		defer client.Disconnect()
-		return client.FeedHold()
+		return runControl(cmd, "feed hold sent", (*fluidnc.Client).FeedHold)
 	},
 }
 
@@ -29,13 +28,7 @@ var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Send cycle start",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.Connect(); err != nil {
-			return err
-		}
-		defer client.Disconnect()
-		return client.CycleStart()
+		return runControl(cmd, "cycle start sent", (*fluidnc.Client).CycleStart)
 	},
 }
 
@@ -43,13 +36,7 @@ var resetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Send soft reset",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.Connect(); err != nil {
-			return err
-		}
-		defer client.Disconnect()
-		return client.SoftReset()
+		return runControl(cmd, "soft reset sent", (*fluidnc.Client).SoftReset)
 	},
 }
 
@@ -57,13 +44,7 @@ var homeCmd = &cobra.Command{
 	Use:   "home",
 	Short: "Home machine",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.Connect(); err != nil {
-			return err
-		}
-		defer client.Disconnect()
-		return client.Home()
+		return runControl(cmd, "homing started", (*fluidnc.Client).Home)
 	},
 }
 
@@ -71,16 +52,45 @@ var unlockCmd = &cobra.Command{
 	Use:   "unlock",
 	Short: "Unlock machine",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.Connect(); err != nil {
-			return err
-		}
-		defer client.Disconnect()
-		return client.Unlock()
+		return runControl(cmd, "unlocked", (*fluidnc.Client).Unlock)
 	},
 }
 
+// runControl sends a control command to the configured machine, or to each
+// machine selected with --machines or --all
+func runControl(cmd *cobra.Command, done string, action func(*fluidnc.Client, context.Context) error) error {
+	ctx := cmd.Context()
+
+	names, err := fleetTargets(cmd)
+	if err != nil {
+		return err
+	}
+	if names != nil {
+		return runFleet(ctx, names, func(ctx context.Context, client *fluidnc.Client) (interface{}, string, error) {
+			return nil, done, connected(ctx, client, func() error {
+				return action(client, ctx)
+			})
+		})
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	client := newClient(cfg)
+	if err := connected(ctx, client, func() error {
+		return action(client, ctx)
+	}); err != nil {
+		return err
+	}
+
+	newPrinter(cfg).Result(map[string]string{"result": done}, nil, func() {
+		fmt.Println(strings.ToUpper(done[:1]) + done[1:])
+	})
+	return nil
+}
+
 func init() {
 	controlCmd.AddCommand(holdCmd, startCmd, resetCmd, homeCmd, unlockCmd)
 	rootCmd.AddCommand(controlCmd)
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fluidnc-client/internal/config"
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/cobra"
+)
+
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find FluidNC devices on the local network",
+	Long: `Search the local network with SSDP M-SEARCH and mDNS queries, confirm each
+device runs FluidNC by asking the WebUI for its firmware report (or sending $I
+over Telnet) and list its name, address, firmware version and ports.
+
+Use --save to write a device to the config file as a machine profile, named
+after the device unless --name is given. When more than one device answers,
+pick it with --select by name or IP.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		save, _ := cmd.Flags().GetBool("save")
+		selected, _ := cmd.Flags().GetString("select")
+		name, _ := cmd.Flags().GetString("name")
+		makeDefault, _ := cmd.Flags().GetBool("default")
+
+		devices, err := fluidnc.Discover(cmd.Context(), timeout)
+		if err != nil {
+			return err
+		}
+
+		out := newPrinter(cfg)
+		out.Result(devices, nil, func() {
+			if len(devices) == 0 {
+				fmt.Println("No FluidNC devices found")
+				return
+			}
+
+			fmt.Printf("%-24s %-16s %-16s %-6s %-6s %-7s %s\n", "Name", "IP", "Version", "HTTP", "WS", "Telnet", "Found via")
+			fmt.Println(strings.Repeat("-", 90))
+			for _, device := range devices {
+				telnet := "-"
+				if device.TelnetPort != 0 {
+					telnet = fmt.Sprintf("%d", device.TelnetPort)
+				}
+				version := device.Version
+				if version == "" {
+					version = "unknown"
+				}
+				fmt.Printf("%-24s %-16s %-16s %-6d %-6d %-7s %s\n",
+					device.Name, device.Host, version, device.Port, device.WebSocketPort, telnet, device.Source)
+			}
+		})
+
+		if !save {
+			return nil
+		}
+
+		device, err := selectDevice(devices, selected)
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			name = machineNameFor(device)
+		}
+
+		profile := config.MachineProfile{
+			Host:          device.Host,
+			Port:          device.Port,
+			WebSocketPort: device.WebSocketPort,
+			TelnetPort:    device.TelnetPort,
+		}
+		path, err := config.AddMachine(name, profile, makeDefault)
+		if err != nil {
+			return err
+		}
+
+		if out.text() {
+			fmt.Printf("Saved %s (%s) as machine %q in %s\n", device.Name, device.Host, strings.ToLower(name), path)
+		}
+		return nil
+	},
+}
+
+// selectDevice picks the device to save by name or IP, or the only one found
+func selectDevice(devices []fluidnc.DiscoveredDevice, selected string) (*fluidnc.DiscoveredDevice, error) {
+	if selected == "" {
+		switch len(devices) {
+		case 0:
+			return nil, fmt.Errorf("no devices found to save")
+		case 1:
+			return &devices[0], nil
+		default:
+			return nil, fmt.Errorf("found %d devices, use --select to choose one by name or IP", len(devices))
+		}
+	}
+
+	for i, device := range devices {
+		if device.Host == selected || strings.EqualFold(device.Name, selected) || strings.EqualFold(device.Hostname, selected) {
+			return &devices[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no discovered device matches %q", selected)
+}
+
+// machineNameFor derives a machine profile name from a device's name
+func machineNameFor(device *fluidnc.DiscoveredDevice) string {
+	name := device.Name
+	if name == "" {
+		name = strings.TrimSuffix(device.Hostname, ".local")
+	}
+	if name == "" {
+		name = device.Host
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+func init() {
+	discoverCmd.Flags().Duration("timeout", 3*time.Second, "How long to wait for responses")
+	discoverCmd.Flags().Bool("save", false, "Save the discovered device as a machine profile")
+	discoverCmd.Flags().String("select", "", "Name or IP of the device to save when several are found")
+	discoverCmd.Flags().String("name", "", "Machine profile name to save the device as")
+	discoverCmd.Flags().Bool("default", false, "Make the saved machine the default")
+	rootCmd.AddCommand(discoverCmd)
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"fluidnc-client/pkg/fluidnc"
+)
+
+// printer renders client results in the configured output format. It is safe
+// for concurrent use, e.g. from job status and progress callbacks.
+type printer struct {
+	cfg       *fluidnc.Config
+	mu        sync.Mutex         // Serialises output and guards the fields below
+	template  *template.Template // Parsed --format template
+	csvHeader []string           // Columns, once the CSV header is written
+	documents int                // YAML documents written, to separate the next one
+}
+
+// newPrinter creates a printer for a loaded configuration
+func newPrinter(cfg *fluidnc.Config) *printer {
+	return &printer{cfg: cfg}
+}
+
+// Status shows current status
+func (p *printer) Status(status *fluidnc.FluidNCStatus) {
+	p.Result(status, nil, func() {
+		fmt.Printf("\rState: %s | MPos: %s | WPos: %s | F:%d S:%d | Line:%d",
+			status.State,
+			p.formatPosition(status.MachinePos),
+			p.formatPosition(status.WorkPos),
+			status.FeedRate, status.SpindleSpeed,
+			status.LineNumber,
+		)
+	})
+}
+
+// formatPosition formats the configured axes of a position
+func (p *printer) formatPosition(pos fluidnc.Position) string {
+	axes := strings.ToUpper(p.cfg.Axes)
+	if axes == "" {
+		axes = "XYZ"
+	}
+
+	parts := make([]string, 0, len(axes))
+	for _, axis := range axes {
+		parts = append(parts, fmt.Sprintf("%c%.3f", axis, pos.Axis(axis)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Progress shows current job progress
+func (p *printer) Progress(progress *fluidnc.JobProgress) {
+	p.Stream(progress, func() { p.progressText(progress) })
+}
+
+// progressText renders a job progress line
+func (p *printer) progressText(progress *fluidnc.JobProgress) {
+	fmt.Printf("\r%s %5.1f%% | Line %d/%d | Time %5.1f%% | Elapsed %s | Remaining %s | F:%d/%.0f | %s   ",
+		progressBar(progress.PercentBytes),
+		progress.PercentBytes,
+		progress.LinesAcked, progress.TotalLines,
+		progress.PercentTime,
+		formatDuration(progress.Elapsed), formatDuration(progress.Remaining),
+		progress.CurrentFeed, progress.ProgrammedFeed,
+		progress.State,
+	)
+}
+
+// Summary shows the final job summary
+func (p *printer) Summary(summary *fluidnc.JobSummary) {
+	p.Result(summary, nil, func() { p.summaryText(summary) })
+}
+
+// summaryText renders a job summary
+func (p *printer) summaryText(summary *fluidnc.JobSummary) {
+	result := "completed"
+	if !summary.Completed {
+		result = "stopped"
+	}
+
+	fmt.Printf("\nJob %s: %s\n", result, summary.File)
+	fmt.Printf("  Lines:     %d/%d acknowledged\n", summary.LinesAcked, summary.TotalLines)
+	fmt.Printf("  Bytes:     %d/%d\n", summary.BytesSent, summary.TotalBytes)
+	fmt.Printf("  Elapsed:   %s\n", formatDuration(summary.Elapsed))
+	fmt.Printf("  Estimated: %s\n", formatDuration(summary.EstimatedTotal))
+}
+
+// TransferProgress shows upload or download progress
+func (p *printer) TransferProgress(progress *fluidnc.TransferProgress) {
+	p.Stream(progress, func() { p.transferText(progress) })
+}
+
+// transferText renders a transfer progress line
+func (p *printer) transferText(progress *fluidnc.TransferProgress) {
+	size := formatBytes(progress.Bytes)
+	if progress.Total > 0 {
+		percent := float64(progress.Bytes) / float64(progress.Total) * 100
+		size = fmt.Sprintf("%s %5.1f%% %s/%s", progressBar(percent), percent, size, formatBytes(progress.Total))
+	}
+
+	fmt.Printf("\r%s: %s | %s/s | Remaining %s   ",
+		progress.Name, size,
+		formatBytes(int64(progress.Rate)),
+		formatDuration(progress.Remaining),
+	)
+	if progress.Done {
+		fmt.Println()
+	}
+}
+
+// eventRecord is an event tagged with its kind for structured output
+type eventRecord struct {
+	Type  string        `json:"type"`
+	Event fluidnc.Event `json:"event"`
+	Error string        `json:"error,omitempty"`
+}
+
+// Event shows one event per line, as text or a record tagged with its kind
+func (p *printer) Event(event fluidnc.Event) {
+	record := eventRecord{Type: event.Kind().String(), Event: event}
+	if e, ok := event.(fluidnc.ConnectionEvent); ok && e.Err != nil {
+		record.Error = e.Err.Error()
+	}
+	p.Stream(record, func() { p.eventText(event) })
+}
+
+// eventText renders an event as a timestamped line
+func (p *printer) eventText(event fluidnc.Event) {
+	var at time.Time
+	var text string
+	switch e := event.(type) {
+	case fluidnc.StatusEvent:
+		at = e.Status.Timestamp
+		text = fmt.Sprintf("%s MPos %s WPos %s", e.Status.State, p.formatPosition(e.Status.MachinePos), p.formatPosition(e.Status.WorkPos))
+	case fluidnc.StateChanged:
+		at = e.Timestamp
+		from := e.From
+		if from == "" {
+			from = "?"
+		}
+		text = fmt.Sprintf("%s -> %s", from, e.To)
+	case fluidnc.AlarmEvent:
+		at = e.Timestamp
+		text = fmt.Sprintf("%d: %s", e.Code, e.Description)
+	case fluidnc.ErrorEvent:
+		at = e.Timestamp
+		text = e.Raw
+	case fluidnc.MessageEvent:
+		at = e.Timestamp
+		text = e.Text
+	case fluidnc.ProbeEvent:
+		at = e.Timestamp
+		result := "failed"
+		if e.Success {
+			result = "ok"
+		}
+		text = fmt.Sprintf("%s %s", p.formatPosition(e.Position), result)
+	case fluidnc.ConnectionEvent:
+		at = e.Timestamp
+		text = "disconnected"
+		if e.Connected {
+			text = "connected"
+		}
+		if e.Err != nil {
+			text += ": " + e.Err.Error()
+		}
+	}
+
+	fmt.Printf("%s %-10s %s\n", at.Format("15:04:05.000"), event.Kind(), text)
+}
+
+// progressBar renders a fixed width text progress bar
+func progressBar(percent float64) string {
+	const barWidth = 20
+	filled := int(percent / 100 * barWidth)
+	if filled > barWidth {
+		filled = barWidth
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", barWidth-filled) + "]"
+}
+
+// formatBytes formats a byte count using binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatDuration formats a duration as h:mm:ss
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+
+	"fluidnc-client/internal/config"
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/cobra"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream machine events",
+	Long: `Poll status and print an event for each state change, alarm, error, message,
+probe result and connection change until interrupted.
+
+Select events with --kinds (status, state, alarm, error, message, probe,
+connection or all). With --output json each event is printed as one JSON object
+per line.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, _ := cmd.Flags().GetStringSlice("kinds")
+		kinds, err := fluidnc.ParseEventKinds(names)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		client := newClient(cfg)
+		events := client.Subscribe(ctx, fluidnc.EventFilter{Kinds: kinds, Drop: fluidnc.DropOldest})
+		if err := client.Connect(ctx); err != nil {
+			return err
+		}
+		defer client.Disconnect()
+
+		// Polling drives the status events; the rest arrive with the responses
+		monitorErr := make(chan error, 1)
+		go func() {
+			monitorErr <- client.MonitorStatus(ctx, nil)
+			cancel()
+		}()
+
+		out := newPrinter(cfg)
+		for event := range events {
+			out.Event(event)
+		}
+		return <-monitorErr
+	},
+}
+
+func init() {
+	eventsCmd.Flags().StringSlice("kinds", []string{"state", "alarm", "error", "message", "probe", "connection"}, "Events to print (comma separated)")
+	rootCmd.AddCommand(eventsCmd)
+}
@@ -1,12 +1,14 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"os"
+	"path"
 	"strings"
 
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
 )
 
@@ -17,36 +19,77 @@ var filesCmd = &cobra.Command{
 }
 
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [path]",
 	Short: "List files on FluidNC filesystem",
-	Long:  "List all files and directories on the FluidNC local filesystem.",
+	Long:  "List files and directories on the FluidNC local filesystem or SD card (use --sd flag for SD card).",
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sd, _ := cmd.Flags().GetBool("sd")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+
+		remotePath := "/"
+		if len(args) > 0 {
+			remotePath = args[0]
+		}
+
+		list := func(client *fluidnc.Client) (*fluidnc.FileListResponse, error) {
+			if recursive {
+				return client.ListFilesRecursive(ctx, remotePath, sd)
+			}
+			return client.ListFiles(ctx, remotePath, sd)
+		}
+
+		names, err := fleetTargets(cmd)
+		if err != nil {
+			return err
+		}
+		if names != nil {
+			return runFleet(ctx, names, func(ctx context.Context, client *fluidnc.Client) (interface{}, string, error) {
+				fileList, err := list(client)
+				if err != nil {
+					return nil, "", err
+				}
+
+				summary := fmt.Sprintf("%d entries in %s", len(fileList.Files), fileList.Path)
+				if fileList.TotalSpace > 0 {
+					summary += fmt.Sprintf(", %d bytes free", fileList.FreeSpace())
+				}
+				return fileList, summary, nil
+			})
+		}
+
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
-		fileList, err := client.ListFiles()
+		fileList, err := list(newClient(cfg))
 		if err != nil {
 			return err
 		}
 
-		if cfg.OutputFormat == "json" {
-			jsonOutput, _ := json.MarshalIndent(fileList, "", "  ")
-			fmt.Println(string(jsonOutput))
-		} else {
-			fmt.Printf("Files in %s:\n", fileList.Path)
-			fmt.Printf("%-30s %-10s %s\n", "Name", "Type", "Size")
-			fmt.Println(strings.Repeat("-", 50))
+		newPrinter(cfg).Result(fileList, fileList.Files, func() {
+			fmt.Printf("Files in %s (%s):\n", fileList.Path, fluidnc.StorageLabel(sd))
+			fmt.Printf("%-40s %-10s %s\n", "Name", "Type", "Size")
+			fmt.Println(strings.Repeat("-", 60))
 			for _, file := range fileList.Files {
 				sizeStr := ""
 				if file.Type == "file" && file.Size > 0 {
 					sizeStr = fmt.Sprintf("%d bytes", file.Size)
+					// The controller only listed an approximate size, e.g. "1.23 KB"
+					if file.SizeRounding > 0 {
+						sizeStr = "~" + sizeStr
+					}
 				}
-				fmt.Printf("%-30s %-10s %s\n", file.Name, file.Type, sizeStr)
+				fmt.Printf("%-40s %-10s %s\n", file.Name, file.Type, sizeStr)
 			}
-		}
+			if fileList.TotalSpace > 0 {
+				fmt.Println(strings.Repeat("-", 60))
+				fmt.Printf("Total: %d bytes | Used: %d bytes (%d%%) | Free: %d bytes\n",
+					fileList.TotalSpace, fileList.UsedSpace, fileList.Occupation, fileList.FreeSpace())
+			}
+		})
 		return nil
 	},
 }
@@ -62,18 +105,20 @@ var uploadLocalCmd = &cobra.Command{
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
+		client := newClient(cfg)
 		filePath := args[0]
 		destination := ""
 		if len(args) > 1 {
 			destination = args[1]
 		}
 
-		if err := client.UploadToLocalFS(filePath, destination); err != nil {
+		if err := client.UploadFileWithProgress(cmd.Context(), filePath, destination, "files", newPrinter(cfg).TransferProgress); err != nil {
 			return err
 		}
 
-		fmt.Printf("File uploaded successfully to local filesystem\n")
+		newPrinter(cfg).Result(map[string]string{"path": filePath, "storage": fluidnc.StorageLabel(false)}, nil, func() {
+			fmt.Println("File uploaded successfully to local filesystem")
+		})
 		return nil
 	},
 }
@@ -89,23 +134,266 @@ var uploadSDCmd = &cobra.Command{
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
+		client := newClient(cfg)
 		filePath := args[0]
 		destination := ""
 		if len(args) > 1 {
 			destination = args[1]
 		}
 
-		if err := client.UploadToSD(filePath, destination); err != nil {
+		if err := client.UploadFileWithProgress(cmd.Context(), filePath, destination, "upload", newPrinter(cfg).TransferProgress); err != nil {
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"path": filePath, "storage": fluidnc.StorageLabel(true)}, nil, func() {
+			fmt.Println("File uploaded successfully to SD card")
+		})
+		return nil
+	},
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete [path]",
+	Short: "Delete a file",
+	Long:  "Delete a file from the FluidNC local filesystem or SD card (use --sd flag for SD card).",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		sd, _ := cmd.Flags().GetBool("sd")
+
+		if err := client.DeleteFile(cmd.Context(), args[0], sd); err != nil {
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"deleted": args[0], "storage": fluidnc.StorageLabel(sd)}, nil, func() {
+			fmt.Printf("Deleted %s from %s\n", args[0], fluidnc.StorageLabel(sd))
+		})
+		return nil
+	},
+}
+
+var renameCmd = &cobra.Command{
+	Use:   "rename [path] [new-name]",
+	Short: "Rename a file or directory",
+	Long:  "Rename a file or directory in place on the FluidNC local filesystem or SD card (use --sd flag for SD card).",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		sd, _ := cmd.Flags().GetBool("sd")
+
+		if err := client.RenameFile(cmd.Context(), args[0], args[1], sd); err != nil {
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"path": args[0], "new_name": args[1], "storage": fluidnc.StorageLabel(sd)}, nil, func() {
+			fmt.Printf("Renamed %s to %s on %s\n", args[0], args[1], fluidnc.StorageLabel(sd))
+		})
+		return nil
+	},
+}
+
+var mkdirCmd = &cobra.Command{
+	Use:   "mkdir [path]",
+	Short: "Create a directory",
+	Long:  "Create a directory on the FluidNC local filesystem or SD card (use --sd flag for SD card).",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		sd, _ := cmd.Flags().GetBool("sd")
+
+		if err := client.MakeDir(cmd.Context(), args[0], sd); err != nil {
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"created": args[0], "storage": fluidnc.StorageLabel(sd)}, nil, func() {
+			fmt.Printf("Created directory %s on %s\n", args[0], fluidnc.StorageLabel(sd))
+		})
+		return nil
+	},
+}
+
+var rmdirCmd = &cobra.Command{
+	Use:   "rmdir [path]",
+	Short: "Remove a directory",
+	Long:  "Remove a directory and its contents from the FluidNC local filesystem or SD card (use --sd flag for SD card).",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		sd, _ := cmd.Flags().GetBool("sd")
+
+		if err := client.RemoveDir(cmd.Context(), args[0], sd); err != nil {
 			return err
 		}
 
-		fmt.Printf("File uploaded successfully to SD card\n")
+		newPrinter(cfg).Result(map[string]string{"removed": args[0], "storage": fluidnc.StorageLabel(sd)}, nil, func() {
+			fmt.Printf("Removed directory %s from %s\n", args[0], fluidnc.StorageLabel(sd))
+		})
 		return nil
 	},
 }
 
+var downloadCmd = &cobra.Command{
+	Use:   "download [remote] [local]",
+	Short: "Download a file",
+	Long: `Download a file from the FluidNC local filesystem or SD card (use --sd flag for SD card).
+Remote paths may also be prefixed with /sd/ or /localfs/. The local path defaults to the remote file name.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		remotePath := args[0]
+		if sd, _ := cmd.Flags().GetBool("sd"); sd {
+			trimmed := strings.TrimPrefix(remotePath, "/")
+			switch {
+			case strings.HasPrefix(trimmed, "localfs/"):
+				return fmt.Errorf("--sd cannot be used with a /localfs/ path: %s", remotePath)
+			case !strings.HasPrefix(trimmed, "sd/"):
+				remotePath = "/sd/" + trimmed
+			}
+		}
+
+		localPath := path.Base(remotePath)
+		if len(args) > 1 {
+			localPath = args[1]
+		}
+
+		file, err := os.Create(localPath)
+		if err != nil {
+			return fmt.Errorf("failed to create local file: %w", err)
+		}
+
+		err = client.DownloadFileWithProgress(cmd.Context(), remotePath, file, newPrinter(cfg).TransferProgress)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(localPath)
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"remote_path": remotePath, "local_path": localPath}, nil, func() {
+			fmt.Printf("Downloaded %s to %s\n", remotePath, localPath)
+		})
+		return nil
+	},
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [local-dir] [remote-dir]",
+	Short: "Mirror a local directory to the controller",
+	Long: `Compare a local directory with a directory on the controller by name, size and
+modification time (where listed), then upload new and changed files. Files the
+controller only lists with a rounded size are checksummed, or uploaded when it
+cannot serve them back; --verify checksums every file whose size matches.
+Remote directories prefixed with /sd/ target the SD card. Use --delete to
+remove remote files that no longer exist locally and --dry-run to only print
+the plan.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		sd, _ := cmd.Flags().GetBool("sd")
+		var opts fluidnc.SyncOptions
+		opts.Delete, _ = cmd.Flags().GetBool("delete")
+		opts.Checksum, _ = cmd.Flags().GetBool("verify")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		remoteDir := "/" + strings.TrimPrefix(args[1], "/")
+		if remoteDir == "/sd" || strings.HasPrefix(remoteDir, "/sd/") {
+			sd = true
+			remoteDir = strings.TrimPrefix(remoteDir, "/sd")
+		}
+
+		plan, err := client.PlanSync(ctx, args[0], remoteDir, sd, &opts)
+		if err != nil {
+			return err
+		}
+
+		out := newPrinter(cfg)
+		out.Result(plan, plan.Actions, func() {
+			fmt.Printf("Sync plan: %s -> %s (%s)\n", plan.LocalDir, plan.RemoteDir, fluidnc.StorageLabel(sd))
+			changes := 0
+			for _, action := range plan.Actions {
+				if action.Action == "skip" {
+					if cfg.Verbose {
+						fmt.Printf("  = %-6s %s (%s)\n", action.Action, action.RemotePath, action.Reason)
+					}
+					continue
+				}
+				changes++
+				fmt.Printf("  %s %-6s %s (%s)\n", syncSymbol(action.Action), action.Action, action.RemotePath, action.Reason)
+			}
+			if changes == 0 {
+				fmt.Println("  Already in sync")
+			}
+		})
+
+		if dryRun {
+			return nil
+		}
+
+		if err := client.ApplySync(ctx, plan, out.TransferProgress); err != nil {
+			return err
+		}
+
+		// Other formats already printed the plan as the result
+		if out.text() {
+			fmt.Println("Sync complete")
+		}
+		return nil
+	},
+}
+
+// syncSymbol returns the plan marker for a sync action
+func syncSymbol(action string) string {
+	switch action {
+	case "mkdir", "upload":
+		return "+"
+	case "delete", "rmdir":
+		return "-"
+	default:
+		return "="
+	}
+}
+
 func init() {
-	filesCmd.AddCommand(listCmd, uploadLocalCmd, uploadSDCmd)
+	syncCmd.Flags().Bool("delete", false, "Delete remote files that do not exist locally")
+	syncCmd.Flags().Bool("dry-run", false, "Print the sync plan without making changes")
+	syncCmd.Flags().Bool("verify", false, "Download files whose sizes match and compare SHA256 checksums")
+	listCmd.Flags().Bool("recursive", false, "List subdirectories recursively")
+	for _, c := range []*cobra.Command{listCmd, deleteCmd, renameCmd, mkdirCmd, rmdirCmd, downloadCmd, syncCmd} {
+		c.Flags().Bool("sd", false, "Operate on the SD card instead of the local filesystem")
+	}
+
+	filesCmd.AddCommand(listCmd, uploadLocalCmd, uploadSDCmd, deleteCmd, renameCmd, mkdirCmd, rmdirCmd, downloadCmd, syncCmd)
 	rootCmd.AddCommand(filesCmd)
 }
@@ -5,29 +5,74 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
 )
 
 var firmwareCmd = &cobra.Command{
 	Use:   "firmware [firmware-file]",
 	Short: "Update FluidNC firmware",
-	Long:  "Upload and install new firmware via the /updatefw endpoint.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Upload and install new firmware via the /updatefw endpoint.
+
+The current version is read and the machine configuration backed up before
+flashing. The image is validated first (ESP32 header, checksum, OTA partition
+size and optional SHA256 from --sha256 or --manifest); mismatches are refused
+unless --force is given. After upload the device is polled until it restarts, and the update
+only succeeds once the reported version has changed (or matches --expect-version).`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
+		client := newClient(cfg)
 		firmwarePath := args[0]
 
-		// Confirm before proceeding
-		fmt.Printf("WARNING: This will update the FluidNC firmware with file: %s\n", firmwarePath)
-		fmt.Print("Are you sure you want to continue? (yes/no): ")
+		expectVersion, _ := cmd.Flags().GetString("expect-version")
+		allowSame, _ := cmd.Flags().GetBool("allow-same-version")
+		backupPath, _ := cmd.Flags().GetString("backup")
+		noBackup, _ := cmd.Flags().GetBool("no-backup")
+		restartTimeout, _ := cmd.Flags().GetDuration("restart-timeout")
+
+		if noBackup {
+			backupPath = ""
+		} else if backupPath == "" {
+			backupPath = fmt.Sprintf("config-backup-%s.yaml", time.Now().Format("20060102-150405"))
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		sha256Sum, _ := cmd.Flags().GetString("sha256")
+		manifest, _ := cmd.Flags().GetString("manifest")
+		maxSize, _ := cmd.Flags().GetInt64("max-size")
+
+		out := newPrinter(cfg)
+		info, err := fluidnc.ValidateFirmwareImage(firmwarePath, &fluidnc.FirmwareValidationOptions{
+			MaxSize:      maxSize,
+			SHA256:       sha256Sum,
+			ManifestPath: manifest,
+		})
+		if err != nil {
+			if !force {
+				return fmt.Errorf("firmware validation failed: %w (use --force to flash anyway)", err)
+			}
+			fmt.Fprintf(os.Stderr, "WARNING: firmware validation failed: %v\n", err)
+		} else {
+			out.Stream(info, func() {
+				fmt.Printf("Validated %s image: %d bytes, %d segments, SHA256 %s", info.ChipName, info.Size, info.Segments, info.SHA256)
+				if info.ChecksumVerified {
+					fmt.Print(" (checksum verified)")
+				}
+				fmt.Println()
+			})
+		}
+
+		// Confirm before proceeding; the prompt goes to stderr to keep stdout parseable
+		fmt.Fprintf(os.Stderr, "WARNING: This will update the FluidNC firmware with file: %s\n", firmwarePath)
+		fmt.Fprint(os.Stderr, "Are you sure you want to continue? (yes/no): ")
 
 		scanner := bufio.NewScanner(os.Stdin)
 		if !scanner.Scan() {
@@ -36,20 +81,42 @@ var firmwareCmd = &cobra.Command{
 
 		confirmation := strings.ToLower(strings.TrimSpace(scanner.Text()))
 		if confirmation != "yes" && confirmation != "y" {
-			fmt.Println("Firmware update cancelled")
+			fmt.Fprintln(os.Stderr, "Firmware update cancelled")
 			return nil
 		}
 
-		if err := client.UpdateFirmware(firmwarePath); err != nil {
+		result, err := client.UpdateFirmwareAndVerify(cmd.Context(), firmwarePath, &fluidnc.FirmwareUpdateOptions{
+			ExpectedVersion:  expectVersion,
+			AllowSameVersion: allowSame,
+			BackupPath:       backupPath,
+			RestartTimeout:   restartTimeout,
+			OnProgress:       out.TransferProgress,
+			OnStage: func(stage string) {
+				out.Stream(map[string]string{"stage": stage}, func() {
+					fmt.Println(stage)
+				})
+			},
+		})
+		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Firmware update initiated successfully\n")
-		fmt.Printf("Please wait for the device to restart and complete the update process\n")
+		out.Result(result, nil, func() {
+			fmt.Printf("Firmware updated successfully: %s -> %s\n", result.OldVersion, result.NewVersion)
+		})
 		return nil
 	},
 }
 
 func init() {
+	firmwareCmd.Flags().String("expect-version", "", "Version the device must report after the update (e.g. v3.8.0)")
+	firmwareCmd.Flags().Bool("allow-same-version", false, "Accept reflashing the currently installed version")
+	firmwareCmd.Flags().String("backup", "", "Path for the configuration backup (default config-backup-<timestamp>.yaml)")
+	firmwareCmd.Flags().Bool("no-backup", false, "Skip backing up the configuration before flashing")
+	firmwareCmd.Flags().Bool("force", false, "Flash even if image validation fails")
+	firmwareCmd.Flags().String("sha256", "", "Expected SHA256 checksum of the firmware image")
+	firmwareCmd.Flags().String("manifest", "", "sha256sum style or JSON release manifest containing the image checksum")
+	firmwareCmd.Flags().Int64("max-size", fluidnc.DefaultOTAPartitionSize, "Maximum image size (OTA partition size in bytes)")
+	firmwareCmd.Flags().Duration("restart-timeout", 2*time.Minute, "How long to wait for the device to restart")
 	rootCmd.AddCommand(firmwareCmd)
 }
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"fluidnc-client/internal/config"
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// fleetResult represents the outcome of a command on one machine
+type fleetResult struct {
+	Host    string      `json:"host"`
+	OK      bool        `json:"ok"`
+	Summary string      `json:"-"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// fleetRecord is a fleetResult labelled with its machine
+type fleetRecord struct {
+	Machine string `json:"machine"`
+	fleetResult
+}
+
+// fleetAction runs a command against one machine within ctx, returning a value
+// for JSON output and a one line summary for the table
+type fleetAction func(ctx context.Context, client *fluidnc.Client) (result interface{}, summary string, err error)
+
+// fleetTargets returns the machines selected with --machines or --all, or nil
+// when the command should run against a single machine as usual
+func fleetTargets(cmd *cobra.Command) ([]string, error) {
+	names, _ := cmd.Flags().GetStringSlice("machines")
+	all, _ := cmd.Flags().GetBool("all")
+	if !all && len(names) == 0 {
+		return nil, nil
+	}
+
+	machines, _, err := config.Machines()
+	if err != nil {
+		return nil, err
+	}
+
+	if all {
+		names = sortedMachineNames(machines)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no machines configured")
+		}
+		return names, nil
+	}
+
+	for i, name := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+		if _, found := machines[names[i]]; !found {
+			return nil, fmt.Errorf("unknown machine %q (see 'fluidnc-cli machines list')", name)
+		}
+	}
+	return names, nil
+}
+
+// currentMachine returns the machine selected with --machine or FLUIDNC_MACHINE, if any
+func currentMachine() string {
+	return viper.GetString("machine")
+}
+
+// runFleet runs action concurrently on each machine, bounded by that machine's
+// timeout, and prints a per-machine table, or a JSON map keyed by machine name
+func runFleet(ctx context.Context, names []string, action fleetAction) error {
+	globalCfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Configs come from the shared viper instance, so load them before fanning out
+	configs := make([]*fluidnc.Config, len(names))
+	labels := make([]string, len(names))
+	for i, name := range names {
+		if configs[i], err = config.LoadMachineConfig(name); err != nil {
+			return err
+		}
+
+		labels[i] = configs[i].Machine
+		if labels[i] == "" {
+			labels[i] = configs[i].Host
+		}
+	}
+
+	results := make([]fleetResult, len(names))
+	var wg sync.WaitGroup
+	for i, cfg := range configs {
+		wg.Add(1)
+		go func(i int, cfg *fluidnc.Config) {
+			defer wg.Done()
+
+			result := fleetResult{Host: cfg.Host}
+			if cfg.Transport == "serial" {
+				result.Host = cfg.SerialPort
+			}
+
+			// One unreachable machine must not hold up the whole table
+			machineCtx := ctx
+			if cfg.Timeout > 0 {
+				var cancel context.CancelFunc
+				machineCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+				defer cancel()
+			}
+
+			value, summary, err := action(machineCtx, newClient(cfg))
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.OK = true
+				result.Result = value
+				result.Summary = summary
+			}
+			results[i] = result
+		}(i, cfg)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if !result.OK {
+			failed++
+		}
+	}
+
+	// json and yaml key results by machine; line and row formats name the machine in each record
+	byMachine := make(map[string]fleetResult, len(names))
+	records := make([]fleetRecord, len(names))
+	for i, label := range labels {
+		byMachine[label] = results[i]
+		records[i] = fleetRecord{Machine: label, fleetResult: results[i]}
+	}
+
+	newPrinter(globalCfg).Result(byMachine, records, func() {
+		fmt.Printf("  %-20s %-22s %s\n", "Machine", "Host", "Result")
+		fmt.Println(strings.Repeat("-", 80))
+		for i, result := range results {
+			if result.OK {
+				fmt.Printf("✓ %-20s %-22s %s\n", labels[i], result.Host, result.Summary)
+			} else {
+				fmt.Printf("✗ %-20s %-22s %s\n", labels[i], result.Host, result.Error)
+			}
+		}
+	})
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d machines failed", failed, len(names))
+	}
+	return nil
+}
+
+// connected runs fn with the client connected over its transport
+func connected(ctx context.Context, client *fluidnc.Client, fn func() error) error {
+	if err := client.Connect(ctx); err != nil {
+		return err
+	}
+	defer client.Disconnect()
+	return fn()
+}
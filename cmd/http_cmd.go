@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
 	"github.com/spf13/cobra"
 )
 
@@ -19,17 +18,19 @@ var httpCmdCmd = &cobra.Command{
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
+		client := newClient(cfg)
 		silent, _ := cmd.Flags().GetBool("silent")
 
-		response, err := client.SendHTTPCommand(args[0], silent)
+		response, err := client.SendHTTPCommand(cmd.Context(), args[0], silent)
 		if err != nil {
 			return err
 		}
 
-		if !silent || cfg.Verbose {
-			fmt.Println(response)
-		}
+		newPrinter(cfg).Result(map[string]string{"command": args[0], "response": response}, nil, func() {
+			if !silent || cfg.Verbose {
+				fmt.Println(response)
+			}
+		})
 		return nil
 	},
 }
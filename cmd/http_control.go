@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
 	"github.com/spf13/cobra"
 )
 
@@ -19,11 +18,13 @@ var httpHoldCmd = &cobra.Command{
 	Short: "Send feed hold via HTTP",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.HTTPFeedHold(); err != nil {
+		client := newClient(cfg)
+		if err := client.HTTPFeedHold(cmd.Context()); err != nil {
 			return err
 		}
-		fmt.Println("Feed hold sent via HTTP")
+		newPrinter(cfg).Result(map[string]string{"result": "feed hold sent via HTTP"}, nil, func() {
+			fmt.Println("Feed hold sent via HTTP")
+		})
 		return nil
 	},
 }
@@ -33,11 +34,13 @@ var httpStartCmd = &cobra.Command{
 	Short: "Send cycle start via HTTP",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.HTTPCycleStart(); err != nil {
+		client := newClient(cfg)
+		if err := client.HTTPCycleStart(cmd.Context()); err != nil {
 			return err
 		}
-		fmt.Println("Cycle start sent via HTTP")
+		newPrinter(cfg).Result(map[string]string{"result": "cycle start sent via HTTP"}, nil, func() {
+			fmt.Println("Cycle start sent via HTTP")
+		})
 		return nil
 	},
 }
@@ -47,11 +50,13 @@ var httpRestartCmd = &cobra.Command{
 	Short: "Restart FluidNC via HTTP",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		if err := client.HTTPRestart(); err != nil {
+		client := newClient(cfg)
+		if err := client.HTTPRestart(cmd.Context()); err != nil {
 			return err
 		}
-		fmt.Println("Restart command sent via HTTP")
+		newPrinter(cfg).Result(map[string]string{"result": "restart command sent via HTTP"}, nil, func() {
+			fmt.Println("Restart command sent via HTTP")
+		})
 		return nil
 	},
 }
@@ -61,16 +66,18 @@ var checkRestartCmd = &cobra.Command{
 	Short: "Check if restart occurred",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, _ := config.LoadConfig()
-		client := fluidnc.NewClient(cfg)
-		restarted, err := client.CheckDidRestart()
+		client := newClient(cfg)
+		restarted, err := client.CheckDidRestart(cmd.Context())
 		if err != nil {
 			return err
 		}
-		if restarted {
-			fmt.Println("Device has restarted")
-		} else {
-			fmt.Println("Device has not restarted")
-		}
+		newPrinter(cfg).Result(map[string]bool{"restarted": restarted}, nil, func() {
+			if restarted {
+				fmt.Println("Device has restarted")
+			} else {
+				fmt.Println("Device has not restarted")
+			}
+		})
 		return nil
 	},
 }
@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
 )
 
@@ -16,11 +22,111 @@ var interactiveCmd = &cobra.Command{
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
-		return client.InteractiveMode()
+		ctx := cmd.Context()
+		client := newClient(cfg)
+		if err := client.Connect(ctx); err != nil {
+			return err
+		}
+		defer client.Disconnect()
+
+		return interactiveSession(ctx, client, newPrinter(cfg))
 	},
 }
 
+// interactiveSession reads commands from stdin until exit, end of input or ctx is done
+func interactiveSession(ctx context.Context, client *fluidnc.Client, out *printer) error {
+	fmt.Println("Connected to FluidNC. Commands: exit, status, alarms, hold, start, reset, home, unlock")
+	fmt.Print("> ")
+
+	// Control commands and the message printed when they succeed
+	controls := map[string]struct {
+		action func(context.Context) error
+		done   string
+	}{
+		"hold":   {client.FeedHold, "Feed hold sent"},
+		"start":  {client.CycleStart, "Cycle start sent"},
+		"reset":  {client.SoftReset, "Soft reset sent"},
+		"home":   {client.Home, "Homing started"},
+		"unlock": {client.Unlock, "Machine unlocked"},
+	}
+
+	// Read stdin in the background so an interrupt ends the session at the prompt
+	lines := make(chan string)
+	scanner := bufio.NewScanner(os.Stdin)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	for {
+		var line string
+		var ok bool
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case line, ok = <-lines:
+		}
+		if !ok {
+			return scanner.Err()
+		}
+		command := strings.TrimSpace(line)
+
+		if command == "exit" {
+			return nil
+		}
+
+		if command == "" {
+			fmt.Print("> ")
+			continue
+		}
+
+		// Handle special commands
+		if control, ok := controls[command]; ok {
+			if err := control.action(ctx); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			} else {
+				fmt.Println(control.done)
+			}
+			fmt.Print("> ")
+			continue
+		}
+
+		switch command {
+		case "status":
+			status, err := client.GetStatus(ctx)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			} else {
+				out.Status(status)
+				fmt.Println()
+			}
+		case "alarms":
+			alarms, err := client.GetAlarms(ctx)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			} else if len(alarms) == 0 {
+				fmt.Println("No active alarms")
+			} else {
+				for _, alarm := range alarms {
+					fmt.Printf("Alarm %d: %s\n", alarm.Code, alarm.Description)
+				}
+			}
+		default:
+			response, err := client.SendCommand(ctx, command)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			} else {
+				fmt.Printf("< %s\n", response)
+			}
+		}
+
+		fmt.Print("> ")
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(interactiveCmd)
 }
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fluidnc-client/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var machinesCmd = &cobra.Command{
+	Use:   "machines",
+	Short: "Manage named machine profiles",
+	Long: `Manage the machines section of the config file. Each machine profile holds
+its own host, ports, transport, credentials and axes; settings it leaves out
+fall back to the global values. Select a machine with --machine, FLUIDNC_MACHINE
+or default_machine.`,
+}
+
+var machinesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List machine profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		machines, defaultMachine, err := config.Machines()
+		if err != nil {
+			return err
+		}
+
+		out := newPrinter(cfg)
+		if !out.text() {
+			records := make([]machineRecord, 0, len(machines))
+			for _, name := range sortedMachineNames(machines) {
+				records = append(records, machineRecord{Name: name, Default: name == defaultMachine, MachineProfile: machines[name]})
+			}
+			out.Result(map[string]interface{}{
+				"default_machine": defaultMachine,
+				"machines":        machines,
+			}, records, nil)
+			return nil
+		}
+
+		if len(machines) == 0 {
+			fmt.Println("No machines configured (add one with 'fluidnc-cli machines add')")
+			return nil
+		}
+
+		fmt.Printf("  %-20s %-24s %-10s %-8s %s\n", "Name", "Host", "Transport", "Axes", "User")
+		fmt.Println(strings.Repeat("-", 76))
+		for _, name := range sortedMachineNames(machines) {
+			machine := machines[name]
+			marker := " "
+			if name == defaultMachine {
+				marker = "*"
+			}
+
+			host := machine.Host
+			if machine.SerialPort != "" && (machine.Transport == "serial" || host == "") {
+				host = machine.SerialPort
+			} else if host != "" && machine.Port != 0 {
+				host = fmt.Sprintf("%s:%d", host, machine.Port)
+			}
+
+			fmt.Printf("%s %-20s %-24s %-10s %-8s %s\n",
+				marker, name, orDefault(host), orDefault(machine.Transport), orDefault(machine.Axes), machine.Username)
+		}
+		return nil
+	},
+}
+
+// machineRecord is one machine profile as a record of jsonl, csv or template output
+type machineRecord struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+	config.MachineProfile
+}
+
+var machinesAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add or update a machine profile",
+	Long: `Add a machine profile, or update the given settings of an existing one.
+Connection settings are taken from --host, --port, --websocket-port and
+--transport along with the flags below; only flags that are given are saved.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		var profile config.MachineProfile
+
+		profile.Host, _ = flags.GetString("host")
+		profile.Port, _ = flags.GetInt("port")
+		profile.WebSocketPort, _ = flags.GetInt("websocket-port")
+		profile.Transport, _ = flags.GetString("transport")
+		profile.TelnetPort, _ = flags.GetInt("telnet-port")
+		profile.SerialPort, _ = flags.GetString("serial-port")
+		profile.Baud, _ = flags.GetInt("baud")
+		if flags.Changed("serial-reset") {
+			reset, _ := flags.GetBool("serial-reset")
+			profile.SerialReset = &reset
+		}
+		profile.Username, _ = flags.GetString("username")
+		profile.Password, _ = flags.GetString("password")
+		profile.Axes, _ = flags.GetString("axes")
+		makeDefault, _ := flags.GetBool("default")
+
+		path, err := config.AddMachine(args[0], profile, makeDefault)
+		if err != nil {
+			return err
+		}
+
+		name := strings.ToLower(args[0])
+		machinesPrinter().Result(map[string]string{"saved": name, "config_file": path}, nil, func() {
+			fmt.Printf("Saved machine %q in %s\n", name, path)
+		})
+		return nil
+	},
+}
+
+var machinesRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove a machine profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.RemoveMachine(args[0])
+		if err != nil {
+			return err
+		}
+
+		name := strings.ToLower(args[0])
+		machinesPrinter().Result(map[string]string{"removed": name, "config_file": path}, nil, func() {
+			fmt.Printf("Removed machine %q from %s\n", name, path)
+		})
+		return nil
+	},
+}
+
+// machinesPrinter returns a printer for commands that edit the config file,
+// reading the format directly since the config itself may not load
+func machinesPrinter() *printer {
+	return printerFor(viper.GetString("output_format"), viper.GetString("output_template"))
+}
+
+// machineTestResult represents the outcome of testing one machine
+type machineTestResult struct {
+	Machine string        `json:"machine"`
+	Host    string        `json:"host"`
+	OK      bool          `json:"ok"`
+	Version string        `json:"version,omitempty"`
+	Latency time.Duration `json:"latency_ns,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+var machinesTestCmd = &cobra.Command{
+	Use:   "test [name...]",
+	Short: "Test connectivity to machines",
+	Long:  "Connect to each named machine, or every configured machine, and report its firmware version.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		globalCfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+		out := newPrinter(globalCfg)
+
+		machines, _, err := config.Machines()
+		if err != nil {
+			return err
+		}
+
+		names := args
+		if len(names) == 0 {
+			names = sortedMachineNames(machines)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no machines configured")
+		}
+
+		var results []machineTestResult
+		failed := 0
+		for _, name := range names {
+			cfg, err := config.LoadMachineConfig(name)
+			if err != nil {
+				return err
+			}
+
+			result := machineTestResult{Machine: cfg.Machine, Host: cfg.Host}
+			if cfg.Transport == "serial" {
+				result.Host = cfg.SerialPort
+			}
+
+			started := time.Now()
+			version, err := newClient(cfg).FirmwareVersion(cmd.Context())
+			if err != nil {
+				result.Error = err.Error()
+				failed++
+			} else {
+				result.OK = true
+				result.Version = version
+				result.Latency = time.Since(started)
+			}
+			results = append(results, result)
+
+			if out.text() {
+				if result.OK {
+					fmt.Printf("✓ %-20s %-24s FluidNC %s (%s)\n", result.Machine, result.Host, result.Version, result.Latency.Round(time.Millisecond))
+				} else {
+					fmt.Printf("✗ %-20s %-24s %s\n", result.Machine, result.Host, result.Error)
+				}
+			}
+		}
+
+		if !out.text() {
+			out.Result(results, nil, nil)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d machines unreachable", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// sortedMachineNames returns machine names in alphabetical order
+func sortedMachineNames(machines map[string]config.MachineProfile) []string {
+	names := make([]string, 0, len(machines))
+	for name := range machines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orDefault shows unset profile values as inherited from the global settings
+func orDefault(value string) string {
+	if value == "" {
+		return "(global)"
+	}
+	return value
+}
+
+func init() {
+	machinesAddCmd.Flags().Int("telnet-port", 0, "Telnet port")
+	machinesAddCmd.Flags().String("serial-port", "", "Serial device, e.g. /dev/ttyUSB0")
+	machinesAddCmd.Flags().Int("baud", 0, "Serial baud rate")
+	machinesAddCmd.Flags().Bool("serial-reset", false, "Reset the ESP32 via DTR/RTS on connect")
+	machinesAddCmd.Flags().String("username", "", "Web UI user")
+	machinesAddCmd.Flags().String("password", "", "Web UI password")
+	machinesAddCmd.Flags().String("axes", "", "Axis letters, e.g. XYZ or XYZA")
+	machinesAddCmd.Flags().Bool("default", false, "Make this the default machine")
+
+	machinesCmd.AddCommand(machinesListCmd)
+	machinesCmd.AddCommand(machinesAddCmd)
+	machinesCmd.AddCommand(machinesRemoveCmd)
+	machinesCmd.AddCommand(machinesTestCmd)
+	rootCmd.AddCommand(machinesCmd)
+}
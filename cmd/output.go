@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	"fluidnc-client/pkg/fluidnc"
+	"gopkg.in/yaml.v3"
+)
+
+// Result prints a command result in the configured format. value is what json
+// and yaml render; records, or value itself when records is nil, are rendered
+// one per line by jsonl, one per row by csv and one per line by --format
+// templates. A slice is treated as one record per element. text renders the
+// text format.
+func (p *printer) Result(value, records interface{}, text func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if records == nil {
+		records = value
+	}
+
+	switch p.format() {
+	case "json":
+		jsonOutput, err := json.MarshalIndent(value, "", "  ")
+		p.check(err)
+		fmt.Println(string(jsonOutput))
+	case "yaml":
+		p.yaml(value)
+	case "text":
+		text()
+	case "csv":
+		p.csvRows(recordsOf(records))
+	default:
+		for _, record := range recordsOf(records) {
+			p.record(record)
+		}
+	}
+}
+
+// Stream prints one of a series of results as it arrives. Unlike Result, json
+// output is one compact object per line.
+func (p *printer) Stream(record interface{}, text func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.format() {
+	case "text":
+		text()
+	case "yaml":
+		p.yaml(record)
+	default:
+		p.record(record)
+	}
+}
+
+// text reports whether output is the human readable text format
+func (p *printer) text() bool {
+	return p.format() == "text"
+}
+
+// format returns the output format in use, where a --format template
+// takes precedence over output_format
+func (p *printer) format() string {
+	if p.cfg.OutputTemplate != "" {
+		return "template"
+	}
+	if p.cfg.OutputFormat == "" {
+		return "text"
+	}
+	return strings.ToLower(p.cfg.OutputFormat)
+}
+
+// record prints a single record as a JSON line, CSV row or template line
+func (p *printer) record(record interface{}) {
+	switch p.format() {
+	case "csv":
+		p.csvRows([]interface{}{record})
+	case "template":
+		if p.template == nil {
+			var err error
+			p.template, err = template.New("format").Option("missingkey=zero").Parse(p.cfg.OutputTemplate)
+			if err != nil {
+				p.template = nil
+				p.check(err)
+				return
+			}
+		}
+		var buf bytes.Buffer
+		p.check(p.template.Execute(&buf, record))
+		fmt.Println(strings.TrimRight(buf.String(), "\n"))
+	default:
+		jsonOutput, err := json.Marshal(record)
+		p.check(err)
+		fmt.Println(string(jsonOutput))
+	}
+}
+
+// csvRows prints records as CSV rows, writing the header before the first
+// row. Nested objects become dotted columns, e.g. machine_position.x. The
+// header holds every column of the first batch; later columns are dropped.
+func (p *printer) csvRows(records []interface{}) {
+	rows := make([]map[string]string, 0, len(records))
+	var columns []string
+	for _, record := range records {
+		node, err := jsonNode(record)
+		p.check(err)
+		if node == nil {
+			continue
+		}
+
+		row := make(map[string]string)
+		flattenNode(node, "", row, &columns)
+		rows = append(rows, row)
+	}
+
+	writer := csv.NewWriter(os.Stdout)
+	if p.csvHeader == nil {
+		p.csvHeader = columns
+		if len(p.csvHeader) == 0 {
+			p.csvHeader = []string{"value"}
+		}
+		writer.Write(p.csvHeader)
+	}
+
+	for _, row := range rows {
+		values := make([]string, len(p.csvHeader))
+		for i, column := range p.csvHeader {
+			values[i] = row[column]
+		}
+		writer.Write(values)
+	}
+	writer.Flush()
+	p.check(writer.Error())
+}
+
+// yaml prints value as a YAML document using the same field names as the JSON output
+func (p *printer) yaml(value interface{}) {
+	node, err := jsonNode(value)
+	p.check(err)
+
+	if p.documents > 0 {
+		fmt.Println("---")
+	}
+	p.documents++
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	p.check(encoder.Encode(node))
+	encoder.Close()
+	fmt.Print(buf.String())
+}
+
+// check reports an output error; results are best effort once the command succeeded
+func (p *printer) check(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "output error: %v\n", err)
+	}
+}
+
+// recordsOf returns the elements of a slice, or value itself as the only record
+func recordsOf(value interface{}) []interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{value}
+	}
+
+	records := make([]interface{}, v.Len())
+	for i := range records {
+		records[i] = v.Index(i).Interface()
+	}
+	return records
+}
+
+// jsonNode converts value to a YAML node tree via its JSON encoding, so json
+// tags name the fields and their order is kept
+func jsonNode(value interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	node := doc.Content[0]
+	clearStyle(node)
+	return node, nil
+}
+
+// clearStyle switches JSON's flow style and quoting to YAML's block style
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// flattenNode collects the scalar values of a node keyed by dotted path,
+// adding columns not seen before in order. Lists are kept as a single JSON cell.
+func flattenNode(node *yaml.Node, prefix string, row map[string]string, columns *[]string) {
+	add := func(column, value string) {
+		if column == "" {
+			column = "value"
+		}
+		if !slices.Contains(*columns, column) {
+			*columns = append(*columns, column)
+		}
+		row[column] = value
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flattenNode(node.Content[i+1], key, row, columns)
+		}
+	case yaml.SequenceNode:
+		var items interface{}
+		node.Decode(&items)
+		data, _ := json.Marshal(items)
+		add(prefix, string(data))
+	default:
+		if node.Tag == "!!null" {
+			add(prefix, "")
+		} else {
+			add(prefix, node.Value)
+		}
+	}
+}
+
+// printerFor creates a printer from the format settings alone, for commands
+// that run when the rest of the configuration may not load
+func printerFor(format, tmpl string) *printer {
+	return newPrinter(&fluidnc.Config{OutputFormat: format, OutputTemplate: tmpl})
+}
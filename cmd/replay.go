@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"fluidnc-client/internal/config"
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/cobra"
+)
+
+// recordedEvents are the events written by status --record
+const recordedEvents = fluidnc.EventStatus | fluidnc.EventAlarm | fluidnc.EventError |
+	fluidnc.EventMessage | fluidnc.EventProbe | fluidnc.EventConnection
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [session.jsonl]",
+	Short: "Play back a recorded status session",
+	Long: `Play back a session recorded with "status --record" through the status
+display, pausing between samples as long as they were apart when recorded.
+
+--speed 10 plays ten times faster and --speed 0 without pauses. In text output
+alarms, errors, messages, probe results and connection changes are shown
+between the status lines; other formats print the status samples as "status"
+would.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		speed, _ := cmd.Flags().GetFloat64("speed")
+		changesOnly, _ := cmd.Flags().GetBool("changes-only")
+		if speed < 0 {
+			return fmt.Errorf("--speed must not be negative, got %g", speed)
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open session: %w", err)
+		}
+		defer file.Close()
+
+		out := newPrinter(cfg)
+		text := out.text()
+		reader := fluidnc.NewSessionReader(file)
+
+		var previous time.Time
+		var last *fluidnc.FluidNCStatus
+		lineOpen := false // A status line awaits its newline
+		defer func() {
+			if lineOpen {
+				fmt.Println()
+			}
+		}()
+
+		for {
+			entry, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if speed > 0 && !previous.IsZero() && entry.Time.After(previous) {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(time.Duration(float64(entry.Time.Sub(previous)) / speed)):
+				}
+			}
+			previous = entry.Time
+
+			switch e := entry.Event.(type) {
+			case fluidnc.StatusEvent:
+				if e.Status == nil || (changesOnly && last != nil && !statusChanged(last, e.Status)) {
+					continue
+				}
+				out.Status(e.Status)
+				if changesOnly && text {
+					fmt.Println()
+				}
+				lineOpen = text && !changesOnly
+				last = e.Status
+			default:
+				if !text {
+					continue
+				}
+				if lineOpen {
+					fmt.Println()
+					lineOpen = false
+				}
+				out.Event(entry.Event)
+			}
+		}
+	},
+}
+
+// recordSession records the client's status samples, alarms, messages and
+// connection changes to path. The returned function stops recording and
+// closes the file.
+func recordSession(ctx context.Context, client *fluidnc.Client, path string) (func() error, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events := client.Subscribe(ctx, fluidnc.EventFilter{Kinds: recordedEvents, Drop: fluidnc.Block})
+	recorder := fluidnc.NewSessionRecorder(file)
+
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		for event := range events {
+			if err == nil {
+				err = recorder.Record(event)
+			}
+		}
+		done <- err
+	}()
+
+	return func() error {
+		cancel()
+		err := <-done
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+func init() {
+	replayCmd.Flags().Float64("speed", 1, "Playback speed multiplier; 0 plays without pauses")
+	replayCmd.Flags().Bool("changes-only", false, "Print a line only when the status changes")
+	rootCmd.AddCommand(replayCmd)
+}
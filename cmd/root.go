@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"fluidnc-client/internal/config"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var rootCmd = &cobra.Command{
@@ -14,24 +18,44 @@ var rootCmd = &cobra.Command{
 Supports file uploads, G-code execution, status monitoring, and machine control.`,
 }
 
-// Execute runs the root command
+// Execute runs the root command. The first interrupt cancels the command's
+// context so it can stop cleanly; a second one terminates the process.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
 
 func init() {
 	// Global flags
+	rootCmd.PersistentFlags().String("machine", "", "Machine profile to use (see 'machines list')")
+	rootCmd.PersistentFlags().StringSlice("machines", nil, "Run on several machine profiles in parallel (comma separated)")
+	rootCmd.PersistentFlags().Bool("all", false, "Run on every configured machine in parallel")
 	rootCmd.PersistentFlags().String("host", "", "FluidNC host address")
 	rootCmd.PersistentFlags().Int("port", 0, "FluidNC HTTP port")
 	rootCmd.PersistentFlags().Int("websocket-port", 0, "FluidNC WebSocket port")
-	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output")
-	rootCmd.PersistentFlags().String("output", "text", "Output format (text|json)")
+	rootCmd.PersistentFlags().String("transport", "", "Command transport (ws|telnet|serial)")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output (same as --log-level debug)")
+	rootCmd.PersistentFlags().String("log-level", "", "Log level for diagnostics on stderr (debug|info|warn|error)")
+	rootCmd.PersistentFlags().String("log-format", "", "Log and trace format (text|json)")
+	rootCmd.PersistentFlags().String("trace", "", "Append a timestamped trace of every frame and HTTP exchange to this file")
+	rootCmd.PersistentFlags().String("output", "text", "Output format (text|json|jsonl|csv|yaml)")
+	rootCmd.PersistentFlags().String("format", "", "Go template printed for each record, e.g. '{{.State}} {{.WorkPos.X}}'")
 
-	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
-	viper.BindPFlag("port", rootCmd.PersistentFlags().Lookup("port"))
-	viper.BindPFlag("websocket_port", rootCmd.PersistentFlags().Lookup("websocket-port"))
-	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
-	viper.BindPFlag("output_format", rootCmd.PersistentFlags().Lookup("output"))
+	config.BindFlag("machine", rootCmd.PersistentFlags().Lookup("machine"))
+	config.BindFlag("host", rootCmd.PersistentFlags().Lookup("host"))
+	config.BindFlag("port", rootCmd.PersistentFlags().Lookup("port"))
+	config.BindFlag("websocket_port", rootCmd.PersistentFlags().Lookup("websocket-port"))
+	config.BindFlag("transport", rootCmd.PersistentFlags().Lookup("transport"))
+	config.BindFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	config.BindFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
+	config.BindFlag("log_format", rootCmd.PersistentFlags().Lookup("log-format"))
+	config.BindFlag("trace_file", rootCmd.PersistentFlags().Lookup("trace"))
+	config.BindFlag("output_format", rootCmd.PersistentFlags().Lookup("output"))
+	config.BindFlag("output_template", rootCmd.PersistentFlags().Lookup("format"))
 }
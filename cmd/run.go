@@ -1,30 +1,136 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var runCmd = &cobra.Command{
 	Use:   "run [file]",
 	Short: "Run G-code file with monitoring",
-	Long:  "Execute G-code file line by line with optional real-time status monitoring.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Execute G-code file line by line with optional real-time status monitoring and progress reporting.
+
+Use --remote to execute a file already stored on the controller (e.g. /sd/job.nc
+or /localfs/job.nc; paths without a prefix are on the local filesystem), or --upload-and-run to upload a local file to the SD card
+and execute it on the controller. On-device execution is more robust over Wi-Fi.
+
+While running, press p to feed hold, r to resume (cycle start) and q or Ctrl-C
+to abort safely (feed hold, spindle stop and soft reset).`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
+		client := newClient(cfg)
 		monitor, _ := cmd.Flags().GetBool("monitor")
+		progress, _ := cmd.Flags().GetBool("progress")
+		remote, _ := cmd.Flags().GetBool("remote")
+		uploadAndRun, _ := cmd.Flags().GetBool("upload-and-run")
+
+		if remote && uploadAndRun {
+			return fmt.Errorf("--remote and --upload-and-run cannot be used together")
+		}
+
+		out := newPrinter(cfg)
+		opts := &fluidnc.RunOptions{
+			Monitor: monitor,
+			OnMessage: func(message string) {
+				out.Stream(map[string]string{"message": message}, func() {
+					fmt.Printf("\r\n%s\r\n", message)
+				})
+			},
+		}
+		// The progress line already shows the machine state
+		if progress {
+			opts.OnProgress = out.Progress
+		} else {
+			opts.OnStatus = out.Status
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		remotePath := args[0]
+		if uploadAndRun {
+			if err := client.UploadToSD(ctx, args[0], ""); err != nil {
+				return err
+			}
+			remotePath = "/sd/" + filepath.Base(args[0])
+			out.Stream(map[string]string{"uploaded": args[0], "remote_path": remotePath}, func() {
+				fmt.Printf("Uploaded %s to %s\n", args[0], remotePath)
+			})
+		}
 
-		return client.RunGCodeFile(args[0], monitor)
+		restore := watchJobKeys(ctx, client, cancel)
+		var summary *fluidnc.JobSummary
+		if remote || uploadAndRun {
+			summary, err = client.RunRemoteFile(ctx, remotePath, opts)
+		} else {
+			summary, err = client.RunGCodeFile(ctx, args[0], opts)
+		}
+		restore()
+
+		if summary != nil {
+			out.Summary(summary)
+		}
+		return err
 	},
 }
 
+// watchJobKeys reads single keystrokes from a terminal to control a running job.
+// It returns a function that restores the terminal state.
+func watchJobKeys(ctx context.Context, client *fluidnc.Client, cancel context.CancelFunc) func() {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return func() {}
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return func() {}
+	}
+
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(buf); err != nil {
+				return
+			}
+
+			switch buf[0] {
+			case 'p', 'P':
+				if err := client.FeedHold(ctx); err != nil {
+					fmt.Printf("\r\nError: %v\r\n", err)
+				}
+			case 'r', 'R':
+				if err := client.CycleStart(ctx); err != nil {
+					fmt.Printf("\r\nError: %v\r\n", err)
+				}
+			case 'q', 'Q', 0x03: // Ctrl-C does not raise SIGINT in raw mode
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		term.Restore(fd, state)
+	}
+}
+
 func init() {
 	runCmd.Flags().Bool("monitor", true, "Enable real-time status monitoring")
+	runCmd.Flags().Bool("progress", true, "Show job progress and estimated time remaining")
+	runCmd.Flags().Bool("remote", false, "Run a file stored on the controller (/sd/... or /localfs/...)")
+	runCmd.Flags().Bool("upload-and-run", false, "Upload a local file to the SD card and run it on the controller")
 	rootCmd.AddCommand(runCmd)
 }
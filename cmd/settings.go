@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"fluidnc-client/internal/config"
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/cobra"
+)
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Read controller settings",
+}
+
+var settingsGetCmd = &cobra.Command{
+	Use:   "get [name]",
+	Short: "Read a single setting",
+	Long:  "Read a single setting by number or config path, e.g. 110 or /axes/x/max_rate_mm_per_min.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		name := args[0]
+
+		names, err := fleetTargets(cmd)
+		if err != nil {
+			return err
+		}
+		if names != nil {
+			return runFleet(ctx, names, func(ctx context.Context, client *fluidnc.Client) (interface{}, string, error) {
+				var value string
+				err := connected(ctx, client, func() error {
+					var err error
+					value, err = client.GetSetting(ctx, name)
+					return err
+				})
+				return value, value, err
+			})
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		client := newClient(cfg)
+		if err := client.Connect(ctx); err != nil {
+			return err
+		}
+		defer client.Disconnect()
+
+		value, err := client.GetSetting(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"name": name, "value": value}, nil, func() {
+			fmt.Println(value)
+		})
+		return nil
+	},
+}
+
+func init() {
+	settingsCmd.AddCommand(settingsGetCmd)
+	rootCmd.AddCommand(settingsCmd)
+}
@@ -2,32 +2,189 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
 )
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Monitor FluidNC status",
-	Long:  "Continuously monitor FluidNC status with real-time updates.",
-	RunE: func(cmd *cobra.Command, args []string) error {
+	Long: `Continuously monitor FluidNC status with real-time updates. Status is
+polled every active_interval while the machine runs, jogs or homes, and every
+status_interval otherwise.
+
+--once prints a single snapshot and --changes-only prints a line only when the
+state, positions, feed, spindle speed or line number change. --until waits for
+a state, e.g. "status --until Idle --timeout 5m" after homing or a job, and
+fails if the machine alarms or the timeout passes first.
+
+--record FILE saves every status sample, alarm, error and message with its
+timestamp as JSON Lines, to be played back later with "replay FILE".
+
+With --machines or --all a single status snapshot is taken from each machine
+in parallel. --check-idle fails unless every machine reports the Idle state,
+e.g. before starting a job. It does not tell whether a machine was homed or
+only unlocked.`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		ctx := cmd.Context()
+		check, _ := cmd.Flags().GetBool("check-idle")
+		once, _ := cmd.Flags().GetBool("once")
+		changesOnly, _ := cmd.Flags().GetBool("changes-only")
+		until, _ := cmd.Flags().GetString("until")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		record, _ := cmd.Flags().GetString("record")
+
+		names, err := fleetTargets(cmd)
+		if err != nil {
+			return err
+		}
+		if until != "" && (once || check || names != nil) {
+			return fmt.Errorf("--until cannot be combined with --once, --check-idle, --machines or --all")
+		}
+		if record != "" && (check || names != nil) {
+			return fmt.Errorf("--record cannot be combined with --check-idle, --machines or --all")
+		}
+		if names != nil || check {
+			if names == nil {
+				names = []string{currentMachine()}
+			}
+			return runFleet(ctx, names, func(ctx context.Context, client *fluidnc.Client) (interface{}, string, error) {
+				var status *fluidnc.FluidNCStatus
+				err := connected(ctx, client, func() error {
+					var err error
+					status, err = client.GetStatus(ctx)
+					return err
+				})
+				if err != nil {
+					return nil, "", err
+				}
+
+				pos := status.MachinePos
+				summary := fmt.Sprintf("%-6s MPos X%.3f Y%.3f Z%.3f", status.State, pos.X, pos.Y, pos.Z)
+				if check && status.State != "Idle" {
+					return nil, "", fmt.Errorf("not idle: state %s", status.State)
+				}
+				return status, summary, nil
+			})
+		}
+
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+		client := newClient(cfg)
+		out := newPrinter(cfg)
+		text := out.text()
+
+		if record != "" {
+			stopRecording, err := recordSession(ctx, client, record)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if recordErr := stopRecording(); err == nil {
+					err = recordErr
+				}
+			}()
+		}
+
+		if once {
+			return connected(ctx, client, func() error {
+				status, err := client.GetStatus(ctx)
+				if err != nil {
+					return err
+				}
+				out.Status(status)
+				if text {
+					fmt.Println()
+				}
+				return nil
+			})
+		}
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		ctx, stop := context.WithCancelCause(ctx)
+		defer stop(nil)
+
+		var last *fluidnc.FluidNCStatus
+		err = client.MonitorStatus(ctx, func(status *fluidnc.FluidNCStatus) {
+			if !changesOnly || last == nil || statusChanged(last, status) {
+				out.Status(status)
+				if changesOnly && text {
+					fmt.Println()
+				}
+			}
+			last = status
 
-		return client.MonitorStatus(ctx, func(status *fluidnc.FluidNCStatus) {
-			client.DisplayStatus(status)
+			switch {
+			case until == "":
+			case stateMatches(status.State, until):
+				stop(errStateReached)
+			case strings.HasPrefix(status.State, "Alarm"):
+				stop(fmt.Errorf("machine entered %s while waiting for %s", status.State, until))
+			}
 		})
+		if text && !changesOnly && last != nil {
+			fmt.Println()
+		}
+		if err != nil {
+			return err
+		}
+
+		cause := context.Cause(ctx)
+		switch {
+		case until == "" || errors.Is(cause, errStateReached):
+			return nil
+		case errors.Is(cause, context.DeadlineExceeded):
+			state := "unknown"
+			if last != nil {
+				state = last.State
+			}
+			return fmt.Errorf("timed out after %s waiting for %s (state %s)", timeout, until, state)
+		case errors.Is(cause, context.Canceled):
+			return fmt.Errorf("interrupted while waiting for %s", until)
+		default:
+			return cause
+		}
 	},
 }
 
+// errStateReached stops monitoring once the --until state is seen
+var errStateReached = errors.New("state reached")
+
+// stateMatches reports whether a reported state such as "Hold:0" is the
+// wanted state, ignoring case and any sub-state
+func stateMatches(state, want string) bool {
+	name, _, _ := strings.Cut(state, ":")
+	return strings.EqualFold(state, want) || strings.EqualFold(name, want)
+}
+
+// statusChanged reports whether any displayed field differs between two reports
+func statusChanged(prev, cur *fluidnc.FluidNCStatus) bool {
+	return prev.State != cur.State ||
+		prev.MachinePos != cur.MachinePos ||
+		prev.WorkPos != cur.WorkPos ||
+		prev.FeedRate != cur.FeedRate ||
+		prev.SpindleSpeed != cur.SpindleSpeed ||
+		prev.LineNumber != cur.LineNumber
+}
+
 func init() {
+	statusCmd.Flags().Bool("check-idle", false, "Take one snapshot and fail unless the machine is Idle")
+	statusCmd.Flags().Bool("once", false, "Print a single status snapshot and exit")
+	statusCmd.Flags().Bool("changes-only", false, "Print a line only when the status changes")
+	statusCmd.Flags().String("until", "", "Exit once the machine reaches this state, e.g. Idle")
+	statusCmd.Flags().String("record", "", "Record status samples, alarms and messages to this JSON Lines file for replay")
+	statusCmd.Flags().Duration("timeout", 0, "Stop monitoring after this long; with --until, fail if the state was not reached")
 	rootCmd.AddCommand(statusCmd)
 }
@@ -1,39 +1,85 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	"fluidnc-client/internal/config"
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/cobra"
 )
 
 var uploadCmd = &cobra.Command{
 	Use:   "upload [file] [destination]",
 	Short: "Upload file to FluidNC",
-	Long:  "Upload file to FluidNC filesystem or SD card (use --sd flag for SD card).",
-	Args:  cobra.RangeArgs(1, 2),
+	Long: `Upload file to FluidNC filesystem or SD card (use --sd flag for SD card).
+
+Use --verify to confirm the upload by checking the listed size and, where the
+controller serves files back, comparing a SHA256 checksum. Failed verifications
+are retried up to retry_attempts times.`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 		cfg, err := config.LoadConfig()
 		if err != nil {
 			return err
 		}
 
-		client := fluidnc.NewClient(cfg)
+		client := newClient(cfg)
 		filePath := args[0]
 		destination := ""
 		if len(args) > 1 {
 			destination = args[1]
 		}
 
+		sd, _ := cmd.Flags().GetBool("sd")
 		endpoint := "files"
-		if sd, _ := cmd.Flags().GetBool("sd"); sd {
+		if sd {
 			endpoint = "upload"
 		}
 
-		return client.UploadFile(filePath, destination, endpoint)
+		out := newPrinter(cfg)
+		verify, _ := cmd.Flags().GetBool("verify")
+		if !verify {
+			return client.UploadFileWithProgress(ctx, filePath, destination, endpoint, out.TransferProgress)
+		}
+
+		attempts := cfg.RetryAttempts
+		if attempts < 1 {
+			attempts = 1
+		}
+
+		for attempt := 1; attempt <= attempts; attempt++ {
+			if err = client.UploadFileWithProgress(ctx, filePath, destination, endpoint, out.TransferProgress); err != nil {
+				return err
+			}
+
+			var result *fluidnc.VerifyResult
+			result, err = client.VerifyUpload(ctx, filePath, destination, sd)
+			if err == nil {
+				out.Result(result, nil, func() {
+					if result.ChecksumVerified {
+						fmt.Printf("Verified %s: %d bytes, SHA256 %s\n", result.Path, result.RemoteSize, result.RemoteSHA256)
+					} else {
+						fmt.Printf("Verified %s: size %d bytes (checksum not supported by controller)\n", result.Path, result.RemoteSize)
+					}
+				})
+				return nil
+			}
+
+			fmt.Fprintf(os.Stderr, "Attempt %d/%d: %v\n", attempt, attempts, err)
+			if attempt < attempts {
+				time.Sleep(cfg.RetryDelay)
+			}
+		}
+
+		return err
 	},
 }
 
 func init() {
 	uploadCmd.Flags().Bool("sd", false, "Upload to SD card")
+	uploadCmd.Flags().Bool("verify", false, "Verify the upload by size and checksum, retrying on mismatch")
 	rootCmd.AddCommand(uploadCmd)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"fluidnc-client/internal/config"
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show FluidNC firmware version",
+	Long:  "Read the firmware version reported by $I.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		names, err := fleetTargets(cmd)
+		if err != nil {
+			return err
+		}
+		if names != nil {
+			return runFleet(ctx, names, func(ctx context.Context, client *fluidnc.Client) (interface{}, string, error) {
+				version, err := client.FirmwareVersion(ctx)
+				return version, "FluidNC " + version, err
+			})
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		version, err := newClient(cfg).FirmwareVersion(ctx)
+		if err != nil {
+			return err
+		}
+
+		newPrinter(cfg).Result(map[string]string{"version": version}, nil, func() {
+			fmt.Printf("FluidNC %s\n", version)
+		})
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
@@ -2,14 +2,81 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
-	"fluidnc-client/internal/fluidnc"
+	"fluidnc-client/pkg/fluidnc"
 	"github.com/spf13/viper"
 )
 
-// LoadConfig loads configuration from file, environment variables, and defaults
+// LoadConfig loads configuration from file, environment variables, and defaults,
+// layering the selected machine profile over the global settings
 func LoadConfig() (*fluidnc.Config, error) {
+	config, err := loadConfig(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateLoaded(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadConfig reads and unmarshals the configuration without validating it
+func loadConfig(withMachine bool) (*fluidnc.Config, error) {
+	if err := readConfigFile(); err != nil {
+		return nil, err
+	}
+
+	if withMachine {
+		if err := applyMachine(); err != nil {
+			return nil, err
+		}
+	}
+
+	var config fluidnc.Config
+	if err := viper.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if !withMachine {
+		config.Machine = ""
+	}
+
+	return &config, nil
+}
+
+// validateLoaded checks a loaded configuration, naming where the problems
+// came from. Unknown keys in the config file are only warned about, so a typo
+// does not lock out the commands that would fix it; "config validate" reports
+// them as errors.
+func validateLoaded(cfg *fluidnc.Config) error {
+	source := viper.ConfigFileUsed()
+	global, machines := unknownKeys()
+	for _, key := range global {
+		fmt.Fprintf(os.Stderr, "warning: %s: unknown setting %q\n", source, key)
+	}
+	for _, key := range machines[cfg.Machine] {
+		fmt.Fprintf(os.Stderr, "warning: %s: unknown setting %q in machine %s\n", source, key, cfg.Machine)
+	}
+
+	problems := problemsOf(Validate(cfg))
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if cfg.Machine != "" {
+		if source != "" {
+			source += ", "
+		}
+		source += "machine " + cfg.Machine
+	}
+	return &ValidationError{Source: source, Problems: problems}
+}
+
+// readConfigFile sets defaults and environment bindings and reads the config file, if any
+func readConfigFile() error {
 	viper.SetConfigName("fluidnc-cli")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(".")
@@ -17,16 +84,30 @@ func LoadConfig() (*fluidnc.Config, error) {
 	viper.AddConfigPath("/etc/fluidnc-cli")
 
 	// Set defaults
-	viper.SetDefault("host", "192.168.1.100")
-	viper.SetDefault("port", 80)
-	viper.SetDefault("websocket_port", 81)
-	viper.SetDefault("timeout", "30s")
-	viper.SetDefault("retry_attempts", 3)
-	viper.SetDefault("retry_delay", "1s")
-	viper.SetDefault("output_format", "text")
-	viper.SetDefault("verbose", false)
-	viper.SetDefault("status_interval", "1s")
-	viper.SetDefault("command_delay", "100ms")
+	defaults := fluidnc.DefaultConfig()
+	viper.SetDefault("host", defaults.Host)
+	viper.SetDefault("port", defaults.Port)
+	viper.SetDefault("websocket_port", defaults.WebSocketPort)
+	viper.SetDefault("transport", defaults.Transport)
+	viper.SetDefault("telnet_port", defaults.TelnetPort)
+	viper.SetDefault("serial_port", defaults.SerialPort)
+	viper.SetDefault("baud", defaults.Baud)
+	viper.SetDefault("serial_reset", defaults.SerialReset)
+	viper.SetDefault("username", defaults.Username)
+	viper.SetDefault("password", defaults.Password)
+	viper.SetDefault("axes", defaults.Axes)
+	viper.SetDefault("timeout", defaults.Timeout)
+	viper.SetDefault("retry_attempts", defaults.RetryAttempts)
+	viper.SetDefault("retry_delay", defaults.RetryDelay)
+	viper.SetDefault("output_format", defaults.OutputFormat)
+	viper.SetDefault("output_template", defaults.OutputTemplate)
+	viper.SetDefault("verbose", defaults.Verbose)
+	viper.SetDefault("log_level", defaults.LogLevel)
+	viper.SetDefault("log_format", defaults.LogFormat)
+	viper.SetDefault("trace_file", defaults.TraceFile)
+	viper.SetDefault("status_interval", defaults.StatusInterval)
+	viper.SetDefault("active_interval", defaults.ActiveInterval)
+	viper.SetDefault("command_delay", defaults.CommandDelay)
 
 	viper.SetEnvPrefix("FLUIDNC")
 	viper.AutomaticEnv()
@@ -34,14 +115,9 @@ func LoadConfig() (*fluidnc.Config, error) {
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+			return fmt.Errorf("failed to read config file: %w", err)
 		}
 	}
 
-	var config fluidnc.Config
-	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
-	}
-
-	return &config, nil
+	return nil
 }
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// MachineProfile represents a named machine in the machines section of the
+// config file. Unset fields fall back to the global settings; SerialReset is
+// a pointer so an explicit false can override a global true. Profiles may
+// also override any other setting; these are the ones managed by the CLI.
+type MachineProfile struct {
+	Host          string `yaml:"host,omitempty" mapstructure:"host" json:"host,omitempty"`
+	Port          int    `yaml:"port,omitempty" mapstructure:"port" json:"port,omitempty"`
+	WebSocketPort int    `yaml:"websocket_port,omitempty" mapstructure:"websocket_port" json:"websocket_port,omitempty"`
+	Transport     string `yaml:"transport,omitempty" mapstructure:"transport" json:"transport,omitempty"`
+	TelnetPort    int    `yaml:"telnet_port,omitempty" mapstructure:"telnet_port" json:"telnet_port,omitempty"`
+	SerialPort    string `yaml:"serial_port,omitempty" mapstructure:"serial_port" json:"serial_port,omitempty"`
+	Baud          int    `yaml:"baud,omitempty" mapstructure:"baud" json:"baud,omitempty"`
+	SerialReset   *bool  `yaml:"serial_reset,omitempty" mapstructure:"serial_reset" json:"serial_reset,omitempty"`
+	Username      string `yaml:"username,omitempty" mapstructure:"username" json:"username,omitempty"`
+	Password      string `yaml:"password,omitempty" mapstructure:"password" json:"-"`
+	Axes          string `yaml:"axes,omitempty" mapstructure:"axes" json:"axes,omitempty"`
+}
+
+// machineNameRegex matches names usable as config keys
+var machineNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// selectedMachine returns the profile chosen by --machine, FLUIDNC_MACHINE or
+// default_machine, or an empty name when none is selected
+func selectedMachine() (string, map[string]interface{}, error) {
+	name := viper.GetString("machine")
+	if name == "" {
+		name = viper.GetString("default_machine")
+	}
+	if name == "" {
+		return "", nil, nil
+	}
+
+	name = strings.ToLower(name)
+	profile, found := viper.GetStringMap("machines")[name]
+	if !found {
+		return "", nil, fmt.Errorf("unknown machine %q (see 'fluidnc-cli machines list')", name)
+	}
+
+	settings, ok := profile.(map[string]interface{})
+	if !ok && profile != nil {
+		return "", nil, fmt.Errorf("machine %q must be a map of settings", name)
+	}
+	return name, settings, nil
+}
+
+// applyMachine merges the selected machine profile into the config file
+// layer, so flags and environment variables still take precedence over it
+func applyMachine() error {
+	name, settings, err := selectedMachine()
+	if err != nil || name == "" {
+		return err
+	}
+
+	if err := viper.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to apply machine %q: %w", name, err)
+	}
+
+	viper.Set("machine", name)
+	return nil
+}
+
+// LoadMachineConfig loads the configuration for a named machine profile
+func LoadMachineConfig(name string) (*fluidnc.Config, error) {
+	viper.Set("machine", name)
+	return LoadConfig()
+}
+
+// Machines returns the configured machine profiles and the default machine name
+func Machines() (map[string]MachineProfile, string, error) {
+	if err := readConfigFile(); err != nil {
+		return nil, "", err
+	}
+
+	machines := make(map[string]MachineProfile)
+	if err := viper.UnmarshalKey("machines", &machines); err != nil {
+		return nil, "", fmt.Errorf("failed to parse machines: %w", err)
+	}
+
+	return machines, strings.ToLower(viper.GetString("default_machine")), nil
+}
+
+// MachineName normalises a machine name, rejecting ones that cannot be used as config keys
+func MachineName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !machineNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid machine name %q: use letters, digits, '-' and '_'", name)
+	}
+	return name, nil
+}
+
+// AddMachine adds a machine profile to the config file, or updates the set
+// fields of an existing one, optionally making it the default machine.
+// It returns the path of the config file written.
+func AddMachine(name string, profile MachineProfile, makeDefault bool) (string, error) {
+	name, err := MachineName(name)
+	if err != nil {
+		return "", err
+	}
+
+	var fields yaml.Node
+	if err := fields.Encode(profile); err != nil {
+		return "", fmt.Errorf("failed to encode machine %q: %w", name, err)
+	}
+
+	return editConfigFile(func(root *yaml.Node) error {
+		machines := mappingChild(root, "machines")
+		machine := mappingChild(machines, name)
+		for i := 0; i+1 < len(fields.Content); i += 2 {
+			setMappingValue(machine, fields.Content[i].Value, fields.Content[i+1])
+		}
+
+		if makeDefault {
+			setMappingValue(root, "default_machine", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+		}
+		return nil
+	})
+}
+
+// RemoveMachine deletes a machine profile from the config file, clearing
+// default_machine if it named the removed machine
+func RemoveMachine(name string) (string, error) {
+	name = strings.ToLower(name)
+
+	return editConfigFile(func(root *yaml.Node) error {
+		index := mappingIndex(root, "machines")
+		if index < 0 || !deleteMappingKey(root.Content[index+1], name) {
+			return fmt.Errorf("unknown machine %q", name)
+		}
+
+		if index := mappingIndex(root, "default_machine"); index >= 0 && strings.EqualFold(root.Content[index+1].Value, name) {
+			deleteMappingKey(root, "default_machine")
+		}
+		return nil
+	})
+}
+
+// configPath returns the config file in use, or fluidnc-cli.yaml in the working directory
+func configPath() string {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path
+	}
+	return "fluidnc-cli.yaml"
+}
+
+// editConfigFile applies edit to the top level mapping of the config file
+// and writes it back. Editing the YAML node tree keeps comments and key order.
+func editConfigFile(edit func(root *yaml.Node) error) (string, error) {
+	if err := readConfigFile(); err != nil {
+		return "", err
+	}
+	path := configPath()
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("config file %s is not a YAML mapping", path)
+	}
+
+	if err := edit(root); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode config file: %w", err)
+	}
+	encoder.Close()
+
+	// The file may hold credentials, so new files are private
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return path, nil
+}
+
+// mappingIndex returns the position of key in a mapping node, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingChild returns the mapping stored under key, creating it if needed
+func mappingChild(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(node, key); i >= 0 && node.Content[i+1].Kind == yaml.MappingNode {
+		return node.Content[i+1]
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(node, key, child)
+	return child
+}
+
+// setMappingValue sets key in a mapping node, keeping any comment on the old value
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	if i := mappingIndex(node, key); i >= 0 {
+		value.LineComment = node.Content[i+1].LineComment
+		node.Content[i+1] = value
+		return
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// deleteMappingKey removes key from a mapping node, reporting whether it was present
+func deleteMappingKey(node *yaml.Node, key string) bool {
+	i := mappingIndex(node, key)
+	if i < 0 {
+		return false
+	}
+	node.Content = append(node.Content[:i], node.Content[i+2:]...)
+	return true
+}
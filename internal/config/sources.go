@@ -0,0 +1,79 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// boundFlags maps settings to the command line flags bound to them
+var boundFlags = make(map[string]*pflag.Flag)
+
+// BindFlag binds a command line flag to a setting, remembering it so the
+// flag can be reported as the source of the setting's value
+func BindFlag(key string, flag *pflag.Flag) {
+	boundFlags[key] = flag
+	viper.BindPFlag(key, flag)
+}
+
+// Setting represents the effective value of a setting and where it came from
+type Setting struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"` // "default", "file", "machine <name>", "env <VAR>" or "flag --<name>"
+}
+
+// Settings returns the effective value and source of every setting, in the
+// order they are declared. Passwords are masked.
+func Settings() ([]Setting, error) {
+	if err := readConfigFile(); err != nil {
+		return nil, err
+	}
+
+	machine, profile, err := selectedMachine()
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMachine(); err != nil {
+		return nil, err
+	}
+
+	var settings []Setting
+	t := reflect.TypeOf(fluidnc.Config{})
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		setting := Setting{Key: key, Value: viper.Get(key), Source: "default"}
+
+		env := "FLUIDNC_" + strings.ToUpper(key)
+		_, inProfile := profile[key]
+		switch {
+		case boundFlags[key] != nil && boundFlags[key].Changed:
+			setting.Source = "flag --" + boundFlags[key].Name
+		case os.Getenv(env) != "":
+			setting.Source = "env " + env
+		case key == "machine" && machine != "":
+			setting.Source = "file (default_machine)"
+		case inProfile:
+			setting.Source = "machine " + machine
+		case viper.InConfig(key):
+			setting.Source = "file"
+		}
+
+		if key == "password" && setting.Value != "" {
+			setting.Value = "********"
+		}
+		settings = append(settings, setting)
+	}
+
+	return settings, nil
+}
+
+// ConfigFile returns the config file in use, or an empty string when settings
+// come only from defaults, the environment and flags
+func ConfigFile() string {
+	return viper.ConfigFileUsed()
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// starterConfig is the commented config file written by WriteStarterConfig
+const starterConfig = `# FluidNC CLI Configuration File
+# Generated by "fluidnc-cli config init". Check it with "fluidnc-cli config validate".
+
+# FluidNC device connection settings
+host: "{{host}}"
+port: 80                       # HTTP port
+websocket_port: 81             # WebSocket port for real-time communication
+transport: "ws"                # Command transport: "ws" (WebSocket), "telnet" or "serial"
+telnet_port: 23                # Telnet port used when transport is "telnet"
+serial_port: ""                # Serial device used when transport is "serial", e.g. /dev/ttyUSB0
+baud: 115200                   # Serial baud rate
+serial_reset: false            # Reset the ESP32 via DTR/RTS on connect and wait for the banner
+
+axes: "XYZ"                    # Axis letters shown in status output, e.g. "XYZA" with a rotary axis
+
+# Authentication for secured web UIs (leave empty if authentication is disabled)
+username: ""
+password: ""                   # Prefer FLUIDNC_PASSWORD over storing it here
+
+# Communication settings
+timeout: "30s"                 # HTTP request timeout
+retry_attempts: 3              # Number of retry attempts for failed requests
+retry_delay: "1s"              # Delay between retry attempts
+
+# Monitoring settings
+status_interval: "1s"          # How often to poll status in monitoring mode while the machine is still
+active_interval: "200ms"       # How often to poll while running, jogging or homing ("0s" to always use status_interval)
+command_delay: "100ms"         # Delay between G-code commands when running files
+
+# Output settings
+output_format: "text"          # Output format: "text", "json", "jsonl", "csv" or "yaml"
+output_template: ""            # Go template printed per record, e.g. "{{.State}} {{.WorkPos.X}}"; overrides output_format
+verbose: false                 # Enable verbose logging (same as log_level "debug")
+
+# Logging settings
+log_level: "warn"              # Diagnostics written to stderr: "debug", "info", "warn" or "error"
+log_format: "text"             # Log and trace format: "text" or "json"
+trace_file: ""                 # Append a timestamped trace of every frame and HTTP exchange here
+
+# Named machine profiles override the settings above; select one with
+# --machine, FLUIDNC_MACHINE or default_machine.
+# default_machine: "router"
+# machines:
+#   router:
+#     host: "192.168.1.50"
+#     transport: "telnet"
+`
+
+// WriteStarterConfig writes a commented starter config file for host,
+// refusing to replace an existing file unless force is set
+func WriteStarterConfig(path, host string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	content := strings.Replace(starterConfig, "{{host}}", host, 1)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// UserConfigPath returns the per-user config file location searched by LoadConfig
+func UserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fluidnc-cli", "fluidnc-cli.yaml"), nil
+}
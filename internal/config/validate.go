@@ -0,0 +1,269 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"fluidnc-client/pkg/fluidnc"
+	"github.com/spf13/viper"
+)
+
+// outputFormats are the accepted values of output_format
+var outputFormats = []string{"text", "json", "jsonl", "csv", "yaml"}
+
+// logLevels are the accepted values of log_level
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// logFormats are the accepted values of log_format
+var logFormats = []string{"text", "json"}
+
+// transports are the accepted values of transport
+var transports = []string{"ws", "websocket", "telnet", "tcp", "serial"}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Source   string // Config file or machine the problems were found in
+	Problems []string
+}
+
+// Error formats the problems one per line
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration")
+	if e.Source != "" {
+		fmt.Fprintf(&b, " (%s)", e.Source)
+	}
+	b.WriteString(":")
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(problem)
+	}
+	return b.String()
+}
+
+// Validate checks a loaded configuration for values that would fail at runtime
+func Validate(cfg *fluidnc.Config) error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	transport := strings.ToLower(cfg.Transport)
+	if transport != "" && !contains(transports, transport) {
+		add("transport %q is not one of %s", cfg.Transport, strings.Join(transports, ", "))
+	}
+
+	if transport == "serial" {
+		if cfg.SerialPort == "" {
+			add("serial_port is required when transport is serial")
+		}
+		if cfg.Baud <= 0 {
+			add("baud must be greater than zero, got %d", cfg.Baud)
+		}
+	} else if strings.TrimSpace(cfg.Host) == "" {
+		add("host is required")
+	}
+
+	for _, port := range []struct {
+		key   string
+		value int
+	}{
+		{"port", cfg.Port},
+		{"websocket_port", cfg.WebSocketPort},
+		{"telnet_port", cfg.TelnetPort},
+	} {
+		if port.value < 1 || port.value > 65535 {
+			add("%s must be between 1 and 65535, got %d", port.key, port.value)
+		}
+	}
+
+	if cfg.Password != "" && cfg.Username == "" {
+		add("password is set but username is empty")
+	}
+
+	if !contains(outputFormats, strings.ToLower(cfg.OutputFormat)) {
+		add("output_format %q is not one of %s", cfg.OutputFormat, strings.Join(outputFormats, ", "))
+	}
+	if cfg.OutputTemplate != "" {
+		if _, err := template.New("output_template").Parse(cfg.OutputTemplate); err != nil {
+			add("output_template is not a valid template: %v", err)
+		}
+	}
+
+	if !contains(logLevels, strings.ToLower(cfg.LogLevel)) {
+		add("log_level %q is not one of %s", cfg.LogLevel, strings.Join(logLevels, ", "))
+	}
+	if !contains(logFormats, strings.ToLower(cfg.LogFormat)) {
+		add("log_format %q is not one of %s", cfg.LogFormat, strings.Join(logFormats, ", "))
+	}
+
+	if cfg.StatusInterval <= 0 {
+		add("status_interval must be greater than zero, got %s", cfg.StatusInterval)
+	}
+	if cfg.ActiveInterval < 0 {
+		add("active_interval must not be negative, got %s", cfg.ActiveInterval)
+	}
+	if cfg.Timeout < 0 {
+		add("timeout must not be negative, got %s", cfg.Timeout)
+	}
+	if cfg.RetryDelay < 0 {
+		add("retry_delay must not be negative, got %s", cfg.RetryDelay)
+	}
+	if cfg.CommandDelay < 0 {
+		add("command_delay must not be negative, got %s", cfg.CommandDelay)
+	}
+	if cfg.RetryAttempts < 0 {
+		add("retry_attempts must not be negative, got %d", cfg.RetryAttempts)
+	}
+
+	seen := make(map[rune]bool)
+	for _, axis := range strings.ToUpper(cfg.Axes) {
+		if !strings.ContainsRune("XYZABC", axis) || seen[axis] {
+			add("axes %q must be distinct letters from XYZABC", cfg.Axes)
+			break
+		}
+		seen[axis] = true
+	}
+	if cfg.Axes == "" {
+		add("axes must not be empty")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// CheckResult represents the validation outcome of the global settings or one machine profile
+type CheckResult struct {
+	Target   string   `json:"target"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// ValidateAll checks the global settings and then each machine profile
+// layered over them, reporting unknown keys in the config file under the
+// settings or profile they appear in
+func ValidateAll() ([]CheckResult, error) {
+	machines, defaultMachine, err := Machines()
+	if err != nil {
+		return nil, err
+	}
+
+	global := CheckResult{Target: "global settings"}
+	if cfg, err := loadConfig(false); err != nil {
+		global.Problems = append(global.Problems, err.Error())
+	} else {
+		unknown, _ := unknownKeys()
+		for _, key := range unknown {
+			global.Problems = append(global.Problems, fmt.Sprintf("unknown setting %q", key))
+		}
+		global.Problems = append(global.Problems, problemsOf(Validate(cfg))...)
+	}
+	if _, found := machines[defaultMachine]; defaultMachine != "" && !found {
+		global.Problems = append(global.Problems, fmt.Sprintf("default_machine %q is not defined in machines", defaultMachine))
+	}
+	results := []CheckResult{global}
+
+	names := make([]string, 0, len(machines))
+	for name := range machines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		result := CheckResult{Target: "machine " + name}
+		viper.Set("machine", name)
+		if cfg, err := loadConfig(true); err != nil {
+			result.Problems = append(result.Problems, err.Error())
+		} else {
+			_, unknown := unknownKeys()
+			for _, key := range unknown[name] {
+				result.Problems = append(result.Problems, fmt.Sprintf("unknown setting %q", key))
+			}
+			result.Problems = append(result.Problems, problemsOf(Validate(cfg))...)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// problemsOf extracts the problem list from a Validate error
+func problemsOf(err error) []string {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Problems
+	}
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+// unknownKeys returns config file settings that no option reads, which are
+// usually typos: top-level keys, and the keys of each machine profile by
+// profile name
+func unknownKeys() ([]string, map[string][]string) {
+	// Profiles may override any setting except the machine selection itself
+	profileKeys := settingKeys(reflect.TypeOf(fluidnc.Config{}))
+	delete(profileKeys, "machine")
+	known := settingKeys(reflect.TypeOf(fluidnc.Config{}))
+	known["default_machine"] = true
+
+	// Keys merged in from the selected profile are reported under machines
+	_, selected, _ := selectedMachine()
+
+	var unknown []string
+	profiles := make(map[string][]string)
+	for key, value := range viper.AllSettings() {
+		if _, merged := selected[key]; merged || !viper.InConfig(key) {
+			continue
+		}
+
+		if key != "machines" {
+			if !known[key] {
+				unknown = append(unknown, key)
+			}
+			continue
+		}
+
+		machines, _ := value.(map[string]interface{})
+		for name, profile := range machines {
+			settings, _ := profile.(map[string]interface{})
+			for setting := range settings {
+				if !profileKeys[setting] {
+					profiles[name] = append(profiles[name], setting)
+				}
+			}
+			sort.Strings(profiles[name])
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown, profiles
+}
+
+// settingKeys returns the mapstructure keys of a settings struct
+func settingKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// contains reports whether value is one of values
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// hasCredentials reports whether a username is configured for secured web UIs
+func (c *Client) hasCredentials() bool {
+	return c.config.Username != ""
+}
+
+// Login authenticates against the web UI and stores the session cookie in the
+// client's cookie jar. FluidNC and ESP3D read the USER/PASSWORD form arguments.
+func (c *Client) Login(ctx context.Context) error {
+	form := url.Values{
+		"USER":     {c.config.Username},
+		"PASSWORD": {c.config.Password},
+		"SUBMIT":   {"yes"},
+	}
+
+	reqURL := fmt.Sprintf("http://%s:%d/login", c.config.Host, c.config.Port)
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("login failed: invalid username or password")
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("login failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	c.mu.Lock()
+	c.loggedIn = true
+	c.mu.Unlock()
+
+	c.logger.Info("logged in", "user", c.config.Username)
+
+	return nil
+}
+
+// ensureLogin logs in once if credentials are configured and no session exists yet
+func (c *Client) ensureLogin(ctx context.Context) error {
+	if !c.hasCredentials() {
+		return nil
+	}
+
+	c.mu.RLock()
+	loggedIn := c.loggedIn
+	c.mu.RUnlock()
+
+	if loggedIn {
+		return nil
+	}
+	return c.Login(ctx)
+}
+
+// doWith sends a request with client, logging in again and retrying once if
+// the session has expired. Requests whose body cannot be replayed are not retried.
+func (c *Client) doWith(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.hasCredentials() {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	if err := c.Login(req.Context()); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return client.Do(retry)
+}
+
+// do sends a request with the client's HTTP client
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.doWith(c.client, req)
+}
+
+// get sends a GET request to url
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// post sends a POST request with body to url
+func (c *Client) post(ctx context.Context, url, contentType, body string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.do(req)
+}
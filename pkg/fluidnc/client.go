@@ -0,0 +1,433 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client handles communication with FluidNC
+type Client struct {
+	config      *Config
+	client      *http.Client
+	conn        Transport
+	mu          sync.RWMutex
+	writeMu     sync.Mutex
+	readMu      sync.Mutex // Held while reading a reply, so each reply reaches the caller it answers
+	pending     []string   // Lines received but not yet consumed, guarded by readMu
+	logger      *slog.Logger
+	trace       *slog.Logger // Protocol trace; nil when tracing is off
+	subMu       sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	lastState   string // Machine state of the last status report, for StateChanged
+	monitoring  bool
+	loggedIn    bool
+	statusRegex *regexp.Regexp
+	alarmRegex  *regexp.Regexp
+	errorRegex  *regexp.Regexp
+}
+
+// NewClient creates a new FluidNC client
+func NewClient(config *Config) *Client {
+	// Regex patterns for parsing FluidNC responses
+	statusRegex := regexp.MustCompile(`<([^>]+)>`)
+	alarmRegex := regexp.MustCompile(`ALARM:(\d+)`)
+	errorRegex := regexp.MustCompile(`error:(\d+)`)
+
+	// Session cookies from Login are stored here and shared with the WebSocket dial
+	jar, _ := cookiejar.New(nil)
+
+	return &Client{
+		config:      config,
+		client:      &http.Client{Timeout: config.Timeout, Jar: jar},
+		logger:      slog.New(slog.DiscardHandler),
+		statusRegex: statusRegex,
+		alarmRegex:  alarmRegex,
+		errorRegex:  errorRegex,
+	}
+}
+
+// Connect establishes a connection using the configured transport
+func (c *Client) Connect(ctx context.Context) error {
+	conn, err := c.dialTransport(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = &eventTransport{Transport: conn, client: c}
+	c.mu.Unlock()
+
+	c.readMu.Lock()
+	c.pending = nil
+	c.readMu.Unlock()
+
+	c.publishConnection(true, nil)
+	return nil
+}
+
+// Disconnect closes the connection
+func (c *Client) Disconnect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		err := c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
+
+// SendCommand sends a command and returns its reply up to and including the
+// ok or error:N that ends it, giving up when ctx is done
+func (c *Client) SendCommand(ctx context.Context, command string) (string, error) {
+	return c.sendCommand(ctx, command, nil)
+}
+
+// sendCommand is SendCommand, passing status reports received while waiting
+// for the reply to onStatus
+func (c *Client) sendCommand(ctx context.Context, command string, onStatus func(*FluidNCStatus)) (string, error) {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return "", fmt.Errorf("not connected")
+	}
+
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if err := c.writeMessage(ctx, conn, []byte(command+"\n")); err != nil {
+		return "", fmt.Errorf("failed to send command: %w", err)
+	}
+
+	response, err := c.readReply(ctx, conn, onStatus)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return response, nil
+}
+
+// readReply reads lines up to the ok or error:N that ends a command's reply.
+// Status reports answer real-time polls rather than the command, so they are
+// passed to onStatus, if set, and left out. The caller holds readMu.
+func (c *Client) readReply(ctx context.Context, conn Transport, onStatus func(*FluidNCStatus)) (string, error) {
+	var lines []string
+	for {
+		line, err := c.readLine(ctx, conn)
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(line, "<") {
+			if onStatus != nil {
+				onStatus(c.ParseStatus(line))
+			}
+			continue
+		}
+
+		lines = append(lines, line)
+		if line == "ok" || strings.HasPrefix(line, "error:") {
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+}
+
+// readStatus polls for a status report and reads up to it, skipping anything
+// received before it. The caller holds readMu.
+func (c *Client) readStatus(ctx context.Context, conn Transport) (*FluidNCStatus, error) {
+	if err := c.writeMessage(ctx, conn, []byte{'?'}); err != nil {
+		return nil, fmt.Errorf("failed to request status: %w", err)
+	}
+
+	for {
+		line, err := c.readLine(ctx, conn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read status: %w", err)
+		}
+		if strings.HasPrefix(line, "<") {
+			return c.ParseStatus(line), nil
+		}
+	}
+}
+
+// readLine returns the next non-empty line received, reading another message
+// once the lines of the previous one are used up. The caller holds readMu.
+func (c *Client) readLine(ctx context.Context, conn Transport) (string, error) {
+	for len(c.pending) == 0 {
+		message, err := readMessage(ctx, conn)
+		if err != nil {
+			return "", err
+		}
+
+		for _, line := range strings.Split(message, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				c.pending = append(c.pending, line)
+			}
+		}
+	}
+
+	line := c.pending[0]
+	c.pending = c.pending[1:]
+	return line, nil
+}
+
+// SendRealTimeCommand sends real-time command (no newline, immediate)
+func (c *Client) SendRealTimeCommand(ctx context.Context, command byte) error {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	return c.writeMessage(ctx, conn, []byte{command})
+}
+
+// writeMessage serialises writes so real-time commands can be sent while streaming
+func (c *Client) writeMessage(ctx context.Context, conn Transport, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return conn.Write(data)
+}
+
+// interruptRead unblocks any pending read
+func (c *Client) interruptRead() {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn != nil {
+		conn.SetReadDeadline(time.Now())
+	}
+}
+
+// ParseStatus parses FluidNC status response
+func (c *Client) ParseStatus(response string) *FluidNCStatus {
+	status := &FluidNCStatus{
+		Timestamp: time.Now(),
+		Raw:       response,
+	}
+
+	matches := c.statusRegex.FindStringSubmatch(response)
+	if len(matches) < 2 {
+		return status
+	}
+
+	fields := strings.Split(matches[1], "|")
+	status.State = fields[0]
+
+	for _, field := range fields[1:] {
+		name, value, found := strings.Cut(field, ":")
+		if !found {
+			continue
+		}
+
+		switch name {
+		case "MPos":
+			// Parse machine position (MPos)
+			parsePosition(value, &status.MachinePos)
+		case "WPos":
+			// Parse work position (WPos)
+			parsePosition(value, &status.WorkPos)
+		case "FS":
+			// Parse feed rate and spindle speed (FS)
+			fs := strings.Split(value, ",")
+			if len(fs) >= 2 {
+				status.FeedRate, _ = strconv.Atoi(fs[0])
+				status.SpindleSpeed, _ = strconv.Atoi(fs[1])
+			}
+		case "Ov":
+			// Parse overrides (Ov)
+			ov := strings.Split(value, ",")
+			if len(ov) >= 3 {
+				status.Overrides.Feed, _ = strconv.Atoi(ov[0])
+				status.Overrides.Rapid, _ = strconv.Atoi(ov[1])
+				status.Overrides.Spindle, _ = strconv.Atoi(ov[2])
+			}
+		case "Pn":
+			// Parse pins (Pn)
+			status.Pins = value
+		case "Bf":
+			// Parse buffer (Bf)
+			bf := strings.Split(value, ",")
+			if len(bf) >= 2 {
+				status.Buffer.Planner, _ = strconv.Atoi(bf[0])
+				status.Buffer.Serial, _ = strconv.Atoi(bf[1])
+			}
+		case "Ln":
+			// Parse line number (Ln)
+			status.LineNumber, _ = strconv.Atoi(value)
+		case "SD":
+			// Parse SD/LocalFS file progress (SD)
+			percent, file, _ := strings.Cut(value, ",")
+			status.SDProgress, _ = strconv.ParseFloat(percent, 64)
+			status.SDFile = file
+		}
+	}
+
+	return status
+}
+
+// parsePosition parses a comma separated coordinate list into a Position
+func parsePosition(value string, pos *Position) {
+	coords := strings.Split(value, ",")
+	if len(coords) < 3 {
+		return
+	}
+
+	axes := []*float64{&pos.X, &pos.Y, &pos.Z, &pos.A, &pos.B, &pos.C}
+	for i, coord := range coords {
+		if i < len(axes) {
+			*axes[i], _ = strconv.ParseFloat(coord, 64)
+		}
+	}
+}
+
+// MonitorStatus continuously monitors FluidNC status. It may share a
+// connection with commands sent from other goroutines, but not with a job
+// started by RunGCode or RunRemoteFile, which report status themselves.
+func (c *Client) MonitorStatus(ctx context.Context, callback func(*FluidNCStatus)) error {
+	// Share an existing connection, e.g. while a file is streaming
+	if !c.IsConnected() {
+		if err := c.Connect(ctx); err != nil {
+			return err
+		}
+		defer c.Disconnect()
+	}
+
+	c.mu.Lock()
+	c.monitoring = true
+	c.mu.Unlock()
+
+	ticker := time.NewTicker(c.config.StatusInterval)
+	defer ticker.Stop()
+	interval := c.config.StatusInterval
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.monitoring = false
+			c.mu.Unlock()
+			return nil
+		case <-ticker.C:
+			c.mu.RLock()
+			conn := c.conn
+			c.mu.RUnlock()
+			if conn == nil {
+				continue
+			}
+
+			c.readMu.Lock()
+			status, err := c.readStatus(ctx, conn)
+			c.readMu.Unlock()
+			if err != nil {
+				c.logger.Debug("status request failed", "error", err)
+				continue
+			}
+
+			// Poll faster while the machine moves
+			if next := c.statusInterval(status.State); next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
+
+			if callback != nil {
+				callback(status)
+			}
+		}
+	}
+}
+
+// statusInterval returns how often to poll status in state
+func (c *Client) statusInterval(state string) time.Duration {
+	if c.config.ActiveInterval <= 0 {
+		return c.config.StatusInterval
+	}
+
+	switch state {
+	case "Run", "Jog", "Home":
+		return c.config.ActiveInterval
+	default:
+		return c.config.StatusInterval
+	}
+}
+
+// GetStatus requests current status
+func (c *Client) GetStatus(ctx context.Context) (*FluidNCStatus, error) {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+	return c.readStatus(ctx, conn)
+}
+
+// GetAlarms requests alarm information
+func (c *Client) GetAlarms(ctx context.Context) ([]AlarmInfo, error) {
+	response, err := c.SendCommand(ctx, "$alarms")
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []AlarmInfo
+	lines := strings.Split(response, "\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "ALARM:") {
+			matches := c.alarmRegex.FindStringSubmatch(line)
+			if len(matches) > 1 {
+				code, _ := strconv.Atoi(matches[1])
+				alarms = append(alarms, AlarmInfo{
+					Code:        code,
+					Description: c.getAlarmDescription(code),
+					Timestamp:   time.Now(),
+				})
+			}
+		}
+	}
+
+	return alarms, nil
+}
+
+// getAlarmDescription returns description for alarm code
+func (c *Client) getAlarmDescription(code int) string {
+	descriptions := map[int]string{
+		1:  "Hard limit triggered",
+		2:  "G-code motion target exceeds machine travel",
+		3:  "Reset while in motion",
+		4:  "Probe fail",
+		5:  "Probe fail",
+		6:  "Homing fail",
+		7:  "Homing fail",
+		8:  "Homing fail",
+		9:  "Homing fail",
+		10: "Homing fail",
+	}
+
+	if desc, exists := descriptions[code]; exists {
+		return desc
+	}
+	return fmt.Sprintf("Unknown alarm code: %d", code)
+}
@@ -0,0 +1,101 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// abortHoldDelay is how long Abort waits for motion to decelerate before resetting
+const abortHoldDelay = 500 * time.Millisecond
+
+// FeedHold sends feed hold command
+func (c *Client) FeedHold(ctx context.Context) error {
+	return c.SendRealTimeCommand(ctx, '!')
+}
+
+// CycleStart sends cycle start command
+func (c *Client) CycleStart(ctx context.Context) error {
+	return c.SendRealTimeCommand(ctx, '~')
+}
+
+// SoftReset sends soft reset command
+func (c *Client) SoftReset(ctx context.Context) error {
+	return c.SendRealTimeCommand(ctx, 0x18) // Ctrl-X
+}
+
+// SpindleStop toggles spindle stop while in feed hold
+func (c *Client) SpindleStop(ctx context.Context) error {
+	return c.SendRealTimeCommand(ctx, 0x9E)
+}
+
+// Abort safely stops a running job: feed hold, spindle stop, then soft reset
+func (c *Client) Abort(ctx context.Context) error {
+	if err := c.FeedHold(ctx); err != nil {
+		return err
+	}
+
+	// Let the machine decelerate so the reset does not lose position
+	select {
+	case <-ctx.Done():
+	case <-time.After(abortHoldDelay):
+	}
+
+	if err := c.SpindleStop(ctx); err != nil {
+		return err
+	}
+
+	return c.SoftReset(ctx)
+}
+
+// Home sends homing command
+func (c *Client) Home(ctx context.Context) error {
+	_, err := c.SendCommand(ctx, "$H")
+	return err
+}
+
+// Unlock sends unlock command
+func (c *Client) Unlock(ctx context.Context) error {
+	_, err := c.SendCommand(ctx, "$X")
+	return err
+}
+
+// GetSettings gets FluidNC settings
+func (c *Client) GetSettings(ctx context.Context) (string, error) {
+	return c.SendCommand(ctx, "$$")
+}
+
+// GetCommands gets available commands
+func (c *Client) GetCommands(ctx context.Context) (string, error) {
+	return c.SendCommand(ctx, "$")
+}
+
+// GetVersion gets FluidNC version
+func (c *Client) GetVersion(ctx context.Context) (string, error) {
+	return c.SendCommand(ctx, "$I")
+}
+
+// GetSetting reads a single setting, e.g. "$110" or "$/axes/x/max_rate_mm_per_min"
+func (c *Client) GetSetting(ctx context.Context, name string) (string, error) {
+	if !strings.HasPrefix(name, "$") {
+		name = "$" + name
+	}
+
+	response, err := c.SendCommand(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		if key, value, ok := strings.Cut(line, "="); ok && strings.EqualFold(key, name) {
+			return value, nil
+		}
+		if strings.HasPrefix(line, "error:") {
+			return "", fmt.Errorf("failed to read %s: %s", name, line)
+		}
+	}
+
+	return "", fmt.Errorf("unexpected response to %s: %s", name, response)
+}
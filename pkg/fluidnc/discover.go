@@ -0,0 +1,488 @@
+package fluidnc
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ssdpAddress = "239.255.255.250:1900"
+	mdnsAddress = "224.0.0.251:5353"
+
+	dnsTypeA   = 1
+	dnsTypePTR = 12
+	dnsTypeSRV = 33
+	dnsClassIN = 1
+
+	// dnsUnicastResponse asks mDNS responders to reply directly to the querying port
+	dnsUnicastResponse = 0x8000
+
+	// discoverProbeTimeout bounds fetching a device's description and probing its firmware
+	discoverProbeTimeout = 5 * time.Second
+)
+
+// mdnsServices are the DNS-SD service types FluidNC advertises
+var mdnsServices = []string{"_http._tcp.local.", "_telnet._tcp.local."}
+
+// deviceDescription is the UPnP description.xml document served for SSDP
+type deviceDescription struct {
+	Device struct {
+		FriendlyName    string `xml:"friendlyName"`
+		Manufacturer    string `xml:"manufacturer"`
+		ModelName       string `xml:"modelName"`
+		ModelNumber     string `xml:"modelNumber"`
+		SerialNumber    string `xml:"serialNumber"`
+		PresentationURL string `xml:"presentationURL"`
+	} `xml:"device"`
+}
+
+// Discover searches the local network for FluidNC devices using SSDP and mDNS
+// until timeout. Each device's description.xml, where served, supplies its
+// friendly name. Only devices that identify as FluidNC when probed, which also
+// reports their firmware version, are returned.
+func Discover(ctx context.Context, timeout time.Duration) ([]DiscoveredDevice, error) {
+	searchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	devices := make(map[string]*DiscoveredDevice)
+	merge := func(d DiscoveredDevice) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		existing, found := devices[d.Host]
+		if !found {
+			devices[d.Host] = &d
+			return
+		}
+		if existing.Name == "" {
+			existing.Name = d.Name
+		}
+		if existing.Hostname == "" {
+			existing.Hostname = d.Hostname
+		}
+		if existing.Port == 0 {
+			existing.Port = d.Port
+		}
+		if d.TelnetPort != 0 {
+			existing.TelnetPort = d.TelnetPort
+		}
+		if existing.DescriptionURL == "" {
+			existing.DescriptionURL = d.DescriptionURL
+		}
+		if !strings.Contains(existing.Source, d.Source) {
+			existing.Source += "," + d.Source
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs[0] = discoverSSDP(searchCtx, merge)
+	}()
+	go func() {
+		defer wg.Done()
+		errs[1] = discoverMDNS(searchCtx, merge)
+	}()
+	wg.Wait()
+
+	if errs[0] != nil && errs[1] != nil {
+		return nil, fmt.Errorf("discovery failed: ssdp: %v; mdns: %v", errs[0], errs[1])
+	}
+
+	// Any UPnP device or HTTP service may answer, so probe each one in
+	// parallel and keep those that identify as FluidNC
+	client := &http.Client{Timeout: discoverProbeTimeout}
+	var result []DiscoveredDevice
+	for _, device := range devices {
+		if device.Port == 0 {
+			device.Port = 80
+		}
+		// FluidNC serves its WebSocket on the port after the HTTP port
+		device.WebSocketPort = device.Port + 1
+		if device.DescriptionURL == "" {
+			device.DescriptionURL = fmt.Sprintf("http://%s/description.xml", net.JoinHostPort(device.Host, strconv.Itoa(device.Port)))
+		}
+
+		wg.Add(1)
+		go func(device *DiscoveredDevice) {
+			defer wg.Done()
+
+			// The description is optional; FluidNC's does not name the firmware
+			fetchDescription(client, device)
+			if device.Name == "" {
+				device.Name = device.Hostname
+			}
+
+			version, err := probeFirmware(ctx, client, device)
+			if err != nil {
+				return
+			}
+			device.Version = version
+
+			mu.Lock()
+			result = append(result, *device)
+			mu.Unlock()
+		}(device)
+	}
+	wg.Wait()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host < result[j].Host
+	})
+
+	return result, nil
+}
+
+// probeFirmware confirms a device runs FluidNC and returns its version. The
+// WebUI's [ESP800] firmware report is asked for first, then $I over Telnet.
+func probeFirmware(ctx context.Context, client *http.Client, device *DiscoveredDevice) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, discoverProbeTimeout)
+	defer cancel()
+
+	probeURL := url.URL{
+		Scheme:   "http",
+		Host:     net.JoinHostPort(device.Host, strconv.Itoa(device.Port)),
+		Path:     "/command",
+		RawQuery: url.Values{"plain": {"[ESP800]"}}.Encode(),
+	}
+	if req, err := http.NewRequestWithContext(ctx, "GET", probeURL.String(), nil); err == nil {
+		if resp, err := client.Do(req); err == nil {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+			if version := ParseVersion(string(body)); resp.StatusCode == http.StatusOK && version != "" {
+				return version, nil
+			}
+		}
+	}
+
+	port := device.TelnetPort
+	if port == 0 {
+		port = DefaultConfig().TelnetPort
+	}
+	conn, err := dialTelnet(ctx, device.Host, port, discoverProbeTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if err := conn.Write([]byte("$I\n")); err != nil {
+		return "", err
+	}
+
+	var response strings.Builder
+	for {
+		message, err := readMessage(ctx, conn)
+		if err != nil {
+			return "", fmt.Errorf("no FluidNC version from %s: %w", device.Host, err)
+		}
+		response.WriteString(message + "\n")
+		if version := ParseVersion(response.String()); version != "" {
+			if device.TelnetPort == 0 {
+				device.TelnetPort = port
+			}
+			return version, nil
+		}
+	}
+}
+
+// fetchDescription fills device details from its description.xml
+func fetchDescription(client *http.Client, device *DiscoveredDevice) error {
+	resp, err := client.Get(device.DescriptionURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("description request failed with status %d", resp.StatusCode)
+	}
+
+	var desc deviceDescription
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&desc); err != nil {
+		return fmt.Errorf("failed to parse description: %w", err)
+	}
+
+	if desc.Device.FriendlyName != "" {
+		device.Name = desc.Device.FriendlyName
+	}
+	device.Model = strings.TrimSpace(desc.Device.ModelName)
+	device.Manufacturer = strings.TrimSpace(desc.Device.Manufacturer)
+	return nil
+}
+
+// discoverSSDP sends an SSDP M-SEARCH and reports each responding device
+func discoverSSDP(ctx context.Context, found func(DiscoveredDevice)) error {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	addr, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		return err
+	}
+
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddress + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: upnp:rootdevice\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(search), addr); err != nil {
+		return err
+	}
+
+	return readPackets(ctx, conn, func(packet []byte, from net.Addr) {
+		var location string
+		for _, line := range strings.Split(string(packet), "\r\n") {
+			name, value, ok := strings.Cut(line, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(name), "LOCATION") {
+				location = strings.TrimSpace(value)
+			}
+		}
+
+		u, err := url.Parse(location)
+		if location == "" || err != nil {
+			return
+		}
+
+		port := 80
+		if p, err := strconv.Atoi(u.Port()); err == nil {
+			port = p
+		}
+
+		found(DiscoveredDevice{
+			Host:           u.Hostname(),
+			Port:           port,
+			DescriptionURL: location,
+			Source:         "ssdp",
+		})
+	})
+}
+
+// discoverMDNS sends a DNS-SD query for FluidNC's services and reports each answer
+func discoverMDNS(ctx context.Context, found func(DiscoveredDevice)) error {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	addr, err := net.ResolveUDPAddr("udp4", mdnsAddress)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.WriteTo(buildMDNSQuery(mdnsServices), addr); err != nil {
+		return err
+	}
+
+	return readPackets(ctx, conn, func(packet []byte, from net.Addr) {
+		records, err := parseDNSRecords(packet)
+		if err != nil {
+			return
+		}
+
+		for _, device := range records.devices() {
+			if device.Host == "" {
+				if udp, ok := from.(*net.UDPAddr); ok {
+					device.Host = udp.IP.String()
+				}
+			}
+			found(device)
+		}
+	})
+}
+
+// readPackets calls handle for every packet received until ctx is done
+func readPackets(ctx context.Context, conn net.PacketConn, handle func([]byte, net.Addr)) error {
+	go func() {
+		<-ctx.Done()
+		conn.SetReadDeadline(time.Now())
+	}()
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		handle(buf[:n], from)
+	}
+}
+
+// buildMDNSQuery encodes a PTR query for each service requesting unicast replies
+func buildMDNSQuery(services []string) []byte {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[4:], uint16(len(services)))
+
+	for _, service := range services {
+		for _, label := range strings.Split(strings.TrimSuffix(service, "."), ".") {
+			msg = append(msg, byte(len(label)))
+			msg = append(msg, label...)
+		}
+		msg = append(msg, 0)
+		msg = binary.BigEndian.AppendUint16(msg, dnsTypePTR)
+		msg = binary.BigEndian.AppendUint16(msg, dnsClassIN|dnsUnicastResponse)
+	}
+
+	return msg
+}
+
+// dnsSRV is the target and port of a service instance
+type dnsSRV struct {
+	target string
+	port   int
+}
+
+// dnsRecords collects the records of an mDNS response relevant to discovery
+type dnsRecords struct {
+	ptr map[string][]string
+	srv map[string]dnsSRV
+	a   map[string]string
+}
+
+// devices assembles discovered devices from PTR, SRV and A records
+func (r *dnsRecords) devices() []DiscoveredDevice {
+	var devices []DiscoveredDevice
+	for service, instances := range r.ptr {
+		for _, instance := range instances {
+			srv, ok := r.srv[instance]
+			if !ok {
+				continue
+			}
+
+			device := DiscoveredDevice{
+				Name:     strings.TrimSuffix(strings.TrimSuffix(instance, service), "."),
+				Hostname: strings.TrimSuffix(srv.target, "."),
+				Host:     r.a[srv.target],
+				Source:   "mdns",
+			}
+			if strings.HasPrefix(service, "_telnet.") {
+				device.TelnetPort = srv.port
+			} else {
+				device.Port = srv.port
+			}
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
+
+// parseDNSRecords parses the answer and additional sections of a DNS message
+func parseDNSRecords(msg []byte) (*dnsRecords, error) {
+	if len(msg) < 12 {
+		return nil, fmt.Errorf("short DNS message")
+	}
+
+	records := &dnsRecords{
+		ptr: make(map[string][]string),
+		srv: make(map[string]dnsSRV),
+		a:   make(map[string]string),
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	count := int(binary.BigEndian.Uint16(msg[6:])) + int(binary.BigEndian.Uint16(msg[8:])) + int(binary.BigEndian.Uint16(msg[10:]))
+	off := 12
+
+	for i := 0; i < questions; i++ {
+		_, next, err := readDNSName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		off = next + 4
+	}
+
+	for i := 0; i < count; i++ {
+		name, next, err := readDNSName(msg, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(msg) {
+			return nil, fmt.Errorf("truncated DNS record")
+		}
+
+		rtype := binary.BigEndian.Uint16(msg[next:])
+		length := int(binary.BigEndian.Uint16(msg[next+8:]))
+		data := next + 10
+		if data+length > len(msg) {
+			return nil, fmt.Errorf("truncated DNS record data")
+		}
+
+		switch rtype {
+		case dnsTypePTR:
+			if target, _, err := readDNSName(msg, data); err == nil {
+				records.ptr[name] = append(records.ptr[name], target)
+			}
+		case dnsTypeSRV:
+			if length >= 6 {
+				if target, _, err := readDNSName(msg, data+6); err == nil {
+					records.srv[name] = dnsSRV{target: target, port: int(binary.BigEndian.Uint16(msg[data+4:]))}
+				}
+			}
+		case dnsTypeA:
+			if length == 4 {
+				records.a[name] = net.IP(msg[data : data+4]).String()
+			}
+		}
+
+		off = data + length
+	}
+
+	return records, nil
+}
+
+// readDNSName reads a possibly compressed domain name, returning it and the offset after it
+func readDNSName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1
+
+	for jumps := 0; jumps < 16; {
+		if off >= len(msg) {
+			return "", 0, fmt.Errorf("truncated DNS name")
+		}
+
+		length := int(msg[off])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = off + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+		case length&0xC0 == 0xC0:
+			if off+1 >= len(msg) {
+				return "", 0, fmt.Errorf("truncated DNS pointer")
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3FFF)
+			jumps++
+		default:
+			if off+1+length > len(msg) {
+				return "", 0, fmt.Errorf("truncated DNS label")
+			}
+			labels = append(labels, string(msg[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+
+	return "", 0, fmt.Errorf("too many DNS compression pointers")
+}
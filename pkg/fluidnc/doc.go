@@ -0,0 +1,43 @@
+// Package fluidnc is a client for FluidNC CNC controllers. Commands and status
+// go over WebSocket, Telnet or USB serial; files and firmware go over the web
+// UI's HTTP API.
+//
+// Start from DefaultConfig and create a client, optionally with a logger:
+//
+//	cfg := fluidnc.DefaultConfig()
+//	cfg.Host = "fluidnc.local"
+//	client := fluidnc.NewClientWithOptions(&fluidnc.ClientOptions{
+//		Config: cfg,
+//		Logger: slog.Default(),
+//	})
+//
+// Every network operation takes a context. Cancelling it aborts pending HTTP
+// requests and interrupts reads on the command connection, and a deadline on
+// it bounds the whole operation:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	version, err := client.FirmwareVersion(ctx)
+//
+// Subscribe delivers typed events such as StateChanged, AlarmEvent and
+// MessageEvent derived from the controller's traffic, each subscriber with its
+// own buffer and drop policy. MonitorStatus keeps status reports coming:
+//
+//	events := client.Subscribe(ctx, fluidnc.EventFilter{Kinds: fluidnc.EventStateChanged | fluidnc.EventAlarm})
+//	go client.MonitorStatus(ctx, nil)
+//	for event := range events {
+//		switch e := event.(type) {
+//		case fluidnc.StateChanged:
+//			log.Printf("%s -> %s", e.From, e.To)
+//		case fluidnc.AlarmEvent:
+//			log.Printf("alarm %d: %s", e.Code, e.Description)
+//		}
+//	}
+//
+// SessionRecorder saves events as JSON Lines and SessionReader reads them
+// back, so a session can be analysed or replayed without the machine.
+//
+// The package never writes to stdout or reads stdin. Results are returned,
+// progress and status are delivered through callbacks, and diagnostics go to
+// the injected logger, which discards them by default.
+package fluidnc
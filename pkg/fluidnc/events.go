@@ -0,0 +1,344 @@
+package fluidnc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventKind identifies a type of Event; kinds can be combined into a filter
+type EventKind uint
+
+const (
+	EventStatus EventKind = 1 << iota
+	EventStateChanged
+	EventAlarm
+	EventError
+	EventMessage
+	EventProbe
+	EventConnection
+
+	// EventAll selects every kind of event
+	EventAll = EventStatus | EventStateChanged | EventAlarm | EventError | EventMessage | EventProbe | EventConnection
+)
+
+// eventKindNames are the names used by String and ParseEventKinds
+var eventKindNames = []struct {
+	kind EventKind
+	name string
+}{
+	{EventStatus, "status"},
+	{EventStateChanged, "state"},
+	{EventAlarm, "alarm"},
+	{EventError, "error"},
+	{EventMessage, "message"},
+	{EventProbe, "probe"},
+	{EventConnection, "connection"},
+}
+
+// String returns the names of the kinds in k, separated by commas
+func (k EventKind) String() string {
+	var names []string
+	for _, entry := range eventKindNames {
+		if k&entry.kind != 0 {
+			names = append(names, entry.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseEventKinds parses names such as "state" or "alarm" into a filter
+func ParseEventKinds(names []string) (EventKind, error) {
+	var kinds EventKind
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, entry := range eventKindNames {
+			if entry.name == name || name == "all" {
+				kinds |= entry.kind
+				found = true
+			}
+		}
+		if !found {
+			return 0, errors.New("unknown event kind " + strconv.Quote(name))
+		}
+	}
+	return kinds, nil
+}
+
+// Event is a notification derived from controller traffic. Use a type switch
+// to get at the concrete event.
+type Event interface {
+	Kind() EventKind
+}
+
+// StatusEvent carries every status report received
+type StatusEvent struct {
+	Status *FluidNCStatus `json:"status"`
+}
+
+// StateChanged is sent when the machine state differs from the previous
+// status report. From is empty for the first report on a connection.
+type StateChanged struct {
+	Timestamp time.Time      `json:"timestamp"`
+	From      string         `json:"from"`
+	To        string         `json:"to"`
+	Status    *FluidNCStatus `json:"status"`
+}
+
+// AlarmEvent is sent when the controller reports an ALARM:n line
+type AlarmEvent struct {
+	AlarmInfo
+}
+
+// ErrorEvent is sent when the controller rejects a command with error:n
+type ErrorEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Code      int       `json:"code"`
+	Raw       string    `json:"raw"`
+}
+
+// MessageEvent carries a [MSG:...] line from the controller
+type MessageEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Text      string    `json:"text"`
+}
+
+// ProbeEvent carries the result of a probing cycle from a [PRB:...] line
+type ProbeEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Position  Position  `json:"position"`
+	Success   bool      `json:"success"`
+}
+
+// ConnectionEvent is sent when the connection opens, closes or is lost
+type ConnectionEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Connected bool      `json:"connected"`
+	Err       error     `json:"-"` // Why the connection was lost, if it was not closed by the client
+}
+
+// Kind implements Event
+func (StatusEvent) Kind() EventKind { return EventStatus }
+
+// Kind implements Event
+func (StateChanged) Kind() EventKind { return EventStateChanged }
+
+// Kind implements Event
+func (AlarmEvent) Kind() EventKind { return EventAlarm }
+
+// Kind implements Event
+func (ErrorEvent) Kind() EventKind { return EventError }
+
+// Kind implements Event
+func (MessageEvent) Kind() EventKind { return EventMessage }
+
+// Kind implements Event
+func (ProbeEvent) Kind() EventKind { return EventProbe }
+
+// Kind implements Event
+func (ConnectionEvent) Kind() EventKind { return EventConnection }
+
+// DropPolicy decides what happens when a subscriber's buffer is full
+type DropPolicy int
+
+const (
+	// DropNewest discards the event that does not fit
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest buffered event to make room
+	DropOldest
+	// Block waits for the subscriber, stalling the reader of the connection
+	Block
+)
+
+// defaultEventBuffer is the channel capacity used when EventFilter.Buffer is zero
+const defaultEventBuffer = 64
+
+// EventFilter selects the events a subscriber receives and how they are buffered
+type EventFilter struct {
+	Kinds  EventKind  // Events to deliver; zero delivers every kind
+	Buffer int        // Channel capacity; defaults to 64
+	Drop   DropPolicy // What to do when the buffer is full
+}
+
+// subscriber is one Subscribe call
+type subscriber struct {
+	ctx    context.Context
+	filter EventFilter
+	ch     chan Event
+}
+
+// Subscribe returns a channel of the events selected by filter. The channel is
+// closed once ctx is done. Events are derived from whatever is read on the
+// connection, so run MonitorStatus alongside to keep status reports coming.
+func (c *Client) Subscribe(ctx context.Context, filter EventFilter) <-chan Event {
+	if filter.Kinds == 0 {
+		filter.Kinds = EventAll
+	}
+	if filter.Buffer <= 0 {
+		filter.Buffer = defaultEventBuffer
+	}
+
+	sub := &subscriber{ctx: ctx, filter: filter, ch: make(chan Event, filter.Buffer)}
+
+	c.subMu.Lock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[*subscriber]struct{})
+	}
+	c.subscribers[sub] = struct{}{}
+	c.subMu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		c.subMu.Lock()
+		delete(c.subscribers, sub)
+		c.subMu.Unlock()
+		close(sub.ch)
+	})
+
+	return sub.ch
+}
+
+// hasSubscribers reports whether any events would be delivered
+func (c *Client) hasSubscribers() bool {
+	c.subMu.RLock()
+	defer c.subMu.RUnlock()
+	return len(c.subscribers) > 0
+}
+
+// publish delivers an event to each interested subscriber according to its drop policy
+func (c *Client) publish(event Event) {
+	c.subMu.RLock()
+	defer c.subMu.RUnlock()
+
+	for sub := range c.subscribers {
+		if sub.filter.Kinds&event.Kind() == 0 || sub.ctx.Err() != nil {
+			continue
+		}
+
+		switch sub.filter.Drop {
+		case Block:
+			select {
+			case sub.ch <- event:
+			case <-sub.ctx.Done():
+			}
+		case DropOldest:
+			select {
+			case sub.ch <- event:
+			default:
+				select {
+				case <-sub.ch:
+				default:
+				}
+				select {
+				case sub.ch <- event:
+				default:
+				}
+			}
+		default:
+			select {
+			case sub.ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// publishMessage turns each line of a received message into events
+func (c *Client) publishMessage(message string) {
+	now := time.Now()
+
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "<"):
+			status := c.ParseStatus(line)
+			c.publish(StatusEvent{Status: status})
+
+			c.subMu.Lock()
+			from := c.lastState
+			c.lastState = status.State
+			c.subMu.Unlock()
+			if status.State != from {
+				c.publish(StateChanged{Timestamp: status.Timestamp, From: from, To: status.State, Status: status})
+			}
+
+		case strings.HasPrefix(line, "[MSG:"):
+			text := strings.TrimSuffix(strings.TrimPrefix(line, "[MSG:"), "]")
+			c.publish(MessageEvent{Timestamp: now, Text: text})
+
+		case strings.HasPrefix(line, "[PRB:"):
+			if event, ok := parseProbe(line); ok {
+				event.Timestamp = now
+				c.publish(event)
+			}
+
+		case c.alarmRegex.MatchString(line):
+			code, _ := strconv.Atoi(c.alarmRegex.FindStringSubmatch(line)[1])
+			c.publish(AlarmEvent{AlarmInfo{Code: code, Description: c.getAlarmDescription(code), Timestamp: now}})
+
+		case c.errorRegex.MatchString(line):
+			code, _ := strconv.Atoi(c.errorRegex.FindStringSubmatch(line)[1])
+			c.publish(ErrorEvent{Timestamp: now, Code: code, Raw: line})
+		}
+	}
+}
+
+// publishConnection reports a connection opening or closing, forgetting the
+// last state so the next connection starts a new sequence of state changes
+func (c *Client) publishConnection(connected bool, err error) {
+	c.subMu.Lock()
+	c.lastState = ""
+	c.subMu.Unlock()
+
+	c.publish(ConnectionEvent{Timestamp: time.Now(), Connected: connected, Err: err})
+}
+
+// parseProbe parses a probe result such as [PRB:1.000,2.000,-3.000:1]
+func parseProbe(line string) (ProbeEvent, bool) {
+	body := strings.TrimSuffix(strings.TrimPrefix(line, "[PRB:"), "]")
+	coords, result, found := strings.Cut(body, ":")
+	if !found {
+		return ProbeEvent{}, false
+	}
+
+	var event ProbeEvent
+	parsePosition(coords, &event.Position)
+	event.Success = result == "1"
+	return event, true
+}
+
+// eventTransport publishes events for every message read from a Transport
+type eventTransport struct {
+	Transport
+	client *Client
+	once   sync.Once // Reports the end of the connection once
+}
+
+// ReadMessage receives a message and publishes its events
+func (t *eventTransport) ReadMessage() (string, error) {
+	message, err := t.Transport.ReadMessage()
+	if err != nil {
+		// Deadlines interrupt reads without ending the connection
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.once.Do(func() { t.client.publishConnection(false, err) })
+		}
+		return message, err
+	}
+
+	if t.client.hasSubscribers() {
+		t.client.publishMessage(message)
+	}
+	return message, nil
+}
+
+// Close closes the connection and reports it closed
+func (t *eventTransport) Close() error {
+	t.once.Do(func() { t.client.publishConnection(false, nil) })
+	return t.Transport.Close()
+}
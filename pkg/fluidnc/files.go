@@ -0,0 +1,483 @@
+package fluidnc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webUIFileList is the JSON directory listing returned by the FluidNC/ESP3D WebUI
+type webUIFileList struct {
+	Files []struct {
+		Name     string `json:"name"`
+		Size     any    `json:"size"`
+		DateTime string `json:"datetime"`
+	} `json:"files"`
+	Path       string `json:"path"`
+	Total      string `json:"total"`
+	Used       string `json:"used"`
+	Occupation string `json:"occupation"`
+	Status     string `json:"status"`
+}
+
+// parseSize converts a WebUI size such as "1.23 KB" or "512 B" to bytes. The
+// second result is how far the true size may be from a rounded value, and is
+// zero when the size is an exact byte count.
+func parseSize(size string) (int64, int64) {
+	fields := strings.Fields(size)
+	if len(fields) == 0 {
+		return 0, 0
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, 0
+	}
+
+	multiplier := 1.0
+	if len(fields) > 1 {
+		switch strings.ToUpper(fields[1]) {
+		case "KB":
+			multiplier = 1 << 10
+		case "MB":
+			multiplier = 1 << 20
+		case "GB":
+			multiplier = 1 << 30
+		case "TB":
+			multiplier = 1 << 40
+		}
+	}
+
+	// Half a unit of the last digit shown, plus one for truncation to whole bytes
+	var rounding int64
+	if _, fraction, found := strings.Cut(fields[0], "."); found || multiplier > 1 {
+		rounding = int64(multiplier/math.Pow10(len(fraction))/2) + 1
+	}
+
+	return int64(value * multiplier), rounding
+}
+
+// parseFileListJSON parses a WebUI JSON directory listing
+func parseFileListJSON(body []byte) (*FileListResponse, error) {
+	var listing webUIFileList
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, err
+	}
+
+	if fileActionFailed(listing.Status) {
+		return nil, fmt.Errorf("list files failed: %s", listing.Status)
+	}
+
+	response := &FileListResponse{
+		Path: listing.Path,
+	}
+	response.TotalSpace, _ = parseSize(listing.Total)
+	response.UsedSpace, _ = parseSize(listing.Used)
+	response.Occupation, _ = strconv.Atoi(listing.Occupation)
+
+	for _, f := range listing.Files {
+		size := strings.TrimSpace(fmt.Sprint(f.Size))
+		info := FileInfo{Name: f.Name, Type: "file"}
+		if modified, err := time.ParseInLocation("2006-01-02 15:04:05", f.DateTime, time.Local); err == nil {
+			info.Modified = modified
+		}
+		// Directories are reported with a size of -1
+		if size == "-1" {
+			info.Type = "directory"
+		} else {
+			info.Size, info.SizeRounding = parseSize(size)
+		}
+		response.Files = append(response.Files, info)
+	}
+
+	return response, nil
+}
+
+// ListFiles lists files in a directory on the FluidNC local filesystem or SD card
+func (c *Client) ListFiles(ctx context.Context, remotePath string, sd bool) (*FileListResponse, error) {
+	dir := "/" + strings.Trim(remotePath, "/")
+
+	query := url.Values{}
+	query.Set("action", "list")
+	query.Set("path", dir)
+
+	reqURL := fmt.Sprintf("http://%s:%d/%s?%s", c.config.Host, c.config.Port, fileEndpoint(sd), query.Encode())
+	resp, err := c.get(ctx, reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list files failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Parse plain text response if the body is not a JSON listing
+	if !json.Valid(bodyBytes) {
+		text := c.parseFileListText(string(bodyBytes), dir)
+		return &text, nil
+	}
+
+	fileListResp, err := parseFileListJSON(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file list: %w", err)
+	}
+	if fileListResp.Path == "" {
+		fileListResp.Path = dir
+	}
+
+	return fileListResp, nil
+}
+
+// ListFilesRecursive lists a directory tree, returning file names relative to remotePath
+func (c *Client) ListFilesRecursive(ctx context.Context, remotePath string, sd bool) (*FileListResponse, error) {
+	root, err := c.ListFiles(ctx, remotePath, sd)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileInfo
+	for _, file := range root.Files {
+		files = append(files, file)
+		if file.Type != "directory" {
+			continue
+		}
+
+		sub, err := c.ListFilesRecursive(ctx, path.Join(root.Path, file.Name), sd)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range sub.Files {
+			child.Name = path.Join(file.Name, child.Name)
+			files = append(files, child)
+		}
+	}
+
+	root.Files = files
+	return root, nil
+}
+
+// parseFileListText parses plain text file listing response
+func (c *Client) parseFileListText(response, dir string) FileListResponse {
+	var files []FileInfo
+	lines := strings.Split(response, "\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		// Parse typical format: "filename.ext (1234 bytes)"
+		parts := strings.Fields(line)
+		if len(parts) >= 1 {
+			name := parts[0]
+			var size int64
+
+			// Extract size if present
+			if len(parts) > 1 {
+				sizeStr := strings.Trim(strings.Join(parts[1:], " "), "()")
+				if strings.Contains(sizeStr, "bytes") {
+					sizeParts := strings.Fields(sizeStr)
+					if len(sizeParts) > 0 {
+						if s, err := strconv.ParseInt(sizeParts[0], 10, 64); err == nil {
+							size = s
+						}
+					}
+				}
+			}
+
+			fileType := "file"
+			if strings.HasSuffix(name, "/") {
+				fileType = "directory"
+				name = strings.TrimSuffix(name, "/")
+			}
+
+			files = append(files, FileInfo{
+				Name: name,
+				Size: size,
+				Type: fileType,
+			})
+		}
+	}
+
+	return FileListResponse{
+		Files: files,
+		Path:  dir,
+	}
+}
+
+// uploadMultipart streams a file as a multipart form to endpoint. The form
+// carries the "<name>S" size field FluidNC uses to reject uploads that won't fit.
+func (c *Client) uploadMultipart(ctx context.Context, filePath, uploadName, endpoint string, fields map[string]string, onProgress func(*TransferProgress)) error {
+	// The streamed body cannot be replayed, so authenticate before sending it
+	if err := c.ensureLogin(ctx); err != nil {
+		return err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	size := info.Size()
+
+	formFields := map[string]string{uploadName + "S": strconv.FormatInt(size, 10)}
+	for name, value := range fields {
+		formFields[name] = value
+	}
+
+	// writeForm writes the multipart body, copying content as the file data
+	writeForm := func(w io.Writer, boundary string, content io.Reader) (*multipart.Writer, error) {
+		writer := multipart.NewWriter(w)
+		if err := writer.SetBoundary(boundary); err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(formFields))
+		for name := range formFields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := writer.WriteField(name, formFields[name]); err != nil {
+				return nil, fmt.Errorf("failed to write %s field: %w", name, err)
+			}
+		}
+
+		part, err := writer.CreateFormFile("file", uploadName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create form file: %w", err)
+		}
+
+		if _, err := io.Copy(part, content); err != nil {
+			return nil, fmt.Errorf("failed to copy file: %w", err)
+		}
+
+		return writer, writer.Close()
+	}
+
+	// Measure the form overhead so the request has an exact Content-Length,
+	// since the controller's web server does not accept chunked uploads
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	overhead := &transferCounter{}
+	if _, err := writeForm(overhead, boundary, strings.NewReader("")); err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	counter := newTransferCounter(filepath.Base(filePath), size, onProgress)
+	go func() {
+		_, err := writeForm(pw, boundary, io.TeeReader(file, counter))
+		pw.CloseWithError(err)
+	}()
+
+	reqURL := fmt.Sprintf("http://%s:%d/%s", c.config.Host, c.config.Port, endpoint)
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, pr)
+	if err != nil {
+		pr.Close()
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.ContentLength = overhead.bytes + size
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	// Large files can take longer than the request timeout to transfer
+	client := *c.client
+	client.Timeout = 0
+
+	resp, err := c.doWith(&client, req)
+	if err != nil {
+		pr.Close()
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	counter.finish()
+	return nil
+}
+
+// uploadName returns the path FluidNC stores an upload under
+func uploadName(filePath, destination string) string {
+	if destination == "" {
+		destination = filepath.Base(filePath)
+	}
+	return "/" + strings.TrimPrefix(destination, "/")
+}
+
+// UploadFile uploads a file to FluidNC
+func (c *Client) UploadFile(ctx context.Context, filePath, destination, endpoint string) error {
+	return c.UploadFileWithProgress(ctx, filePath, destination, endpoint, nil)
+}
+
+// UploadFileWithProgress uploads a file to FluidNC, reporting transfer progress to onProgress
+func (c *Client) UploadFileWithProgress(ctx context.Context, filePath, destination, endpoint string, onProgress func(*TransferProgress)) error {
+	name := uploadName(filePath, destination)
+	if err := c.uploadMultipart(ctx, filePath, name, endpoint, map[string]string{"filename": name}, onProgress); err != nil {
+		return err
+	}
+
+	c.logger.Info("file uploaded", "file", name, "endpoint", endpoint)
+
+	return nil
+}
+
+// UploadToLocalFS uploads a file to the local filesystem via /files endpoint
+func (c *Client) UploadToLocalFS(ctx context.Context, filePath string, destinationName string) error {
+	return c.UploadFile(ctx, filePath, destinationName, "files")
+}
+
+// UploadToSD uploads a file directly to SD card via /upload endpoint
+func (c *Client) UploadToSD(ctx context.Context, filePath string, destinationName string) error {
+	return c.UploadFile(ctx, filePath, destinationName, "upload")
+}
+
+// fileEndpoint returns the WebUI endpoint serving the SD card or local filesystem
+func fileEndpoint(sd bool) string {
+	if sd {
+		return "upload"
+	}
+	return "files"
+}
+
+// remoteStoragePath resolves a stored file path such as /sd/job.nc or
+// /localfs/job.nc to its filesystem and the path within it. Paths without
+// either prefix are on the local filesystem, as with the other file
+// operations when sd is false.
+func remoteStoragePath(remotePath string) (sd bool, filePath string) {
+	filePath = "/" + strings.TrimPrefix(remotePath, "/")
+	for _, prefix := range []string{"/sd", "/localfs"} {
+		if filePath == prefix || strings.HasPrefix(filePath, prefix+"/") {
+			return prefix == "/sd", "/" + strings.TrimPrefix(strings.TrimPrefix(filePath, prefix), "/")
+		}
+	}
+	return false, filePath
+}
+
+// splitRemotePath splits a remote path into its directory and base name
+func splitRemotePath(remotePath string) (string, string) {
+	dir, name := path.Split("/" + strings.Trim(remotePath, "/"))
+	return dir, name
+}
+
+// fileActionFailed reports whether a WebUI file action status describes a failure
+func fileActionFailed(status string) bool {
+	status = strings.ToLower(status)
+	for _, marker := range []string{"cannot", "error", "invalid", "not found", "failed"} {
+		if strings.Contains(status, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileAction performs a WebUI file management action in the directory of remotePath
+func (c *Client) fileAction(ctx context.Context, sd bool, action, remotePath string, extra url.Values) error {
+	dir, name := splitRemotePath(remotePath)
+	if name == "" {
+		return fmt.Errorf("invalid remote path: %q", remotePath)
+	}
+
+	query := url.Values{}
+	query.Set("action", action)
+	query.Set("filename", name)
+	query.Set("path", dir)
+	for key, values := range extra {
+		query[key] = values
+	}
+
+	reqURL := fmt.Sprintf("http://%s:%d/%s?%s", c.config.Host, c.config.Port, fileEndpoint(sd), query.Encode())
+	resp, err := c.get(ctx, reqURL)
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %w", action, remotePath, err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed with status %d: %s", action, resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err == nil && fileActionFailed(result.Status) {
+		return fmt.Errorf("%s %s failed: %s", action, remotePath, result.Status)
+	}
+
+	c.logger.Info("file action completed", "action", action, "path", remotePath, "status", result.Status)
+
+	return nil
+}
+
+// DeleteFile deletes a file from the local filesystem or SD card
+func (c *Client) DeleteFile(ctx context.Context, remotePath string, sd bool) error {
+	return c.fileAction(ctx, sd, "delete", remotePath, nil)
+}
+
+// RenameFile renames a file or directory in place on the local filesystem or SD card
+func (c *Client) RenameFile(ctx context.Context, remotePath, newName string, sd bool) error {
+	if newName == "" || strings.Contains(newName, "/") {
+		return fmt.Errorf("invalid new name: %q", newName)
+	}
+	return c.fileAction(ctx, sd, "rename", remotePath, url.Values{"newname": {newName}})
+}
+
+// MakeDir creates a directory on the local filesystem or SD card
+func (c *Client) MakeDir(ctx context.Context, remotePath string, sd bool) error {
+	return c.fileAction(ctx, sd, "createdir", remotePath, nil)
+}
+
+// RemoveDir removes a directory and its contents from the local filesystem or SD card
+func (c *Client) RemoveDir(ctx context.Context, remotePath string, sd bool) error {
+	return c.fileAction(ctx, sd, "deletedir", remotePath, nil)
+}
+
+// UpdateFirmware uploads firmware file for update via /updatefw endpoint
+func (c *Client) UpdateFirmware(ctx context.Context, firmwarePath string) error {
+	return c.UpdateFirmwareWithProgress(ctx, firmwarePath, nil)
+}
+
+// UpdateFirmwareWithProgress uploads firmware for update, reporting transfer progress to onProgress
+func (c *Client) UpdateFirmwareWithProgress(ctx context.Context, firmwarePath string, onProgress func(*TransferProgress)) error {
+	if err := c.uploadMultipart(ctx, firmwarePath, uploadName(firmwarePath, ""), "updatefw", nil, onProgress); err != nil {
+		return fmt.Errorf("firmware update failed: %w", err)
+	}
+
+	c.logger.Info("firmware update started", "file", firmwarePath)
+
+	return nil
+}
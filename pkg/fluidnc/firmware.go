@@ -0,0 +1,214 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// restartGracePeriod is how long the device must keep answering after an update
+// before it is assumed to have restarted without the outage being observed
+const restartGracePeriod = 15 * time.Second
+
+// restartPollInterval is how often WaitForRestart probes the device
+const restartPollInterval = time.Second
+
+var (
+	versionRegex        = regexp.MustCompile(`FluidNC (v[\w.\-]+)`)
+	versionFallback     = regexp.MustCompile(`\[VER:([^\]]+)\]`)
+	configFilenameRegex = regexp.MustCompile(`\$Config/Filename=(\S+)`)
+)
+
+// ParseVersion extracts the firmware version from a $I response
+func ParseVersion(response string) string {
+	if matches := versionRegex.FindStringSubmatch(response); len(matches) > 1 {
+		return matches[1]
+	}
+	if matches := versionFallback.FindStringSubmatch(response); len(matches) > 1 {
+		return strings.TrimSuffix(matches[1], ":")
+	}
+	return ""
+}
+
+// withConnection runs fn over the WebSocket, connecting first if necessary
+func (c *Client) withConnection(ctx context.Context, fn func() error) error {
+	if !c.IsConnected() {
+		if err := c.Connect(ctx); err != nil {
+			return err
+		}
+		defer c.Disconnect()
+	}
+	return fn()
+}
+
+// FirmwareVersion reads and parses the firmware version via $I
+func (c *Client) FirmwareVersion(ctx context.Context) (string, error) {
+	var response string
+	err := c.withConnection(ctx, func() error {
+		var err error
+		response, err = c.GetVersion(ctx)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	version := ParseVersion(response)
+	if version == "" {
+		return "", fmt.Errorf("unrecognised version response: %s", response)
+	}
+	return version, nil
+}
+
+// ConfigFilename returns the name of the active machine configuration file
+func (c *Client) ConfigFilename(ctx context.Context) (string, error) {
+	var response string
+	err := c.withConnection(ctx, func() error {
+		var err error
+		response, err = c.SendCommand(ctx, "$Config/Filename")
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if matches := configFilenameRegex.FindStringSubmatch(response); len(matches) > 1 {
+		return matches[1], nil
+	}
+	return "config.yaml", nil
+}
+
+// BackupConfig downloads the active machine configuration file to localPath
+func (c *Client) BackupConfig(ctx context.Context, localPath string) error {
+	name, err := c.ConfigFilename(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read config filename: %w", err)
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+
+	err = c.DownloadFile(ctx, "/localfs/"+name, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(localPath)
+		return fmt.Errorf("failed to back up %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// WaitForRestart polls the device until it has restarted and answers HTTP again
+func (c *Client) WaitForRestart(ctx context.Context) error {
+	start := time.Now()
+	wentDown := false
+
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for device to restart: %w", ctx.Err())
+		case <-ticker.C:
+		}
+
+		if err := c.Ping(ctx); err != nil {
+			wentDown = true
+			continue
+		}
+
+		if wentDown || time.Since(start) >= restartGracePeriod {
+			return nil
+		}
+		if restarted, err := c.CheckDidRestart(ctx); err == nil && restarted {
+			return nil
+		}
+	}
+}
+
+// UpdateFirmwareAndVerify backs up the configuration, flashes firmware, waits
+// for the device to restart and confirms the reported version changed as expected
+func (c *Client) UpdateFirmwareAndVerify(ctx context.Context, firmwarePath string, opts *FirmwareUpdateOptions) (*FirmwareUpdateResult, error) {
+	if opts == nil {
+		opts = &FirmwareUpdateOptions{}
+	}
+	stage := func(format string, args ...any) {
+		if opts.OnStage != nil {
+			opts.OnStage(fmt.Sprintf(format, args...))
+		}
+	}
+
+	result := &FirmwareUpdateResult{}
+
+	stage("Reading current firmware version")
+	oldVersion, err := c.FirmwareVersion(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to read current version: %w", err)
+	}
+	result.OldVersion = oldVersion
+	stage("Current firmware version: %s", oldVersion)
+
+	if opts.ExpectedVersion != "" && opts.ExpectedVersion == oldVersion && !opts.AllowSameVersion {
+		return result, fmt.Errorf("device is already running %s", oldVersion)
+	}
+
+	if opts.BackupPath != "" {
+		stage("Backing up configuration to %s", opts.BackupPath)
+		if err := c.BackupConfig(ctx, opts.BackupPath); err != nil {
+			return result, err
+		}
+		result.BackupPath = opts.BackupPath
+	}
+
+	stage("Uploading firmware %s", firmwarePath)
+	if err := c.UpdateFirmwareWithProgress(ctx, firmwarePath, opts.OnProgress); err != nil {
+		return result, err
+	}
+
+	stage("Waiting for device to restart")
+	waitCtx := ctx
+	if opts.RestartTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, opts.RestartTimeout)
+		defer cancel()
+	}
+	if err := c.WaitForRestart(waitCtx); err != nil {
+		return result, err
+	}
+
+	// The WebSocket server can lag behind HTTP after boot, so retry the version read
+	var newVersion string
+	for attempt := 0; ; attempt++ {
+		newVersion, err = c.FirmwareVersion(waitCtx)
+		if err == nil || attempt >= c.config.RetryAttempts {
+			break
+		}
+		select {
+		case <-waitCtx.Done():
+			return result, fmt.Errorf("failed to read new version: %w", err)
+		case <-time.After(c.config.RetryDelay):
+		}
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to read new version: %w", err)
+	}
+	result.NewVersion = newVersion
+	stage("New firmware version: %s", newVersion)
+
+	switch {
+	case opts.ExpectedVersion != "" && newVersion != opts.ExpectedVersion:
+		return result, fmt.Errorf("firmware reports %s after update, expected %s", newVersion, opts.ExpectedVersion)
+	case opts.ExpectedVersion == "" && newVersion == oldVersion && !opts.AllowSameVersion:
+		return result, fmt.Errorf("firmware version unchanged after update (%s)", newVersion)
+	}
+
+	return result, nil
+}
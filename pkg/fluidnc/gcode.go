@@ -0,0 +1,236 @@
+package fluidnc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// isSendableLine reports whether a trimmed G-code line should be sent
+func isSendableLine(line string) bool {
+	return line != "" && !strings.HasPrefix(line, ";") && !strings.HasPrefix(line, "(")
+}
+
+// scanGCode counts sendable lines, estimates total run time and measures size
+// from the reader's current offset, then seeks back to that offset
+func scanGCode(r io.ReadSeeker) (int, time.Duration, int64, error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	estimator := newGCodeEstimator()
+	scanner := bufio.NewScanner(r)
+	lines := 0
+	var estimated time.Duration
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !isSendableLine(line) {
+			continue
+		}
+		lines++
+		estimated += estimator.Estimate(line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, 0, 0, err
+	}
+
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return lines, estimated, end - start, nil
+}
+
+// RunGCodeFile sends G-code commands from file line by line
+func (c *Client) RunGCodeFile(ctx context.Context, filePath string, opts *RunOptions) (*JobSummary, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	runOpts := RunOptions{}
+	if opts != nil {
+		runOpts = *opts
+	}
+	if runOpts.Name == "" {
+		runOpts.Name = filePath
+	}
+
+	return c.RunGCode(ctx, file, &runOpts)
+}
+
+// RunGCode streams G-code from a reader line by line. Totals and time
+// estimates are only available when the reader is also an io.Seeker.
+// Cancelling the context performs a safe abort of the running job.
+func (c *Client) RunGCode(ctx context.Context, r io.Reader, opts *RunOptions) (*JobSummary, error) {
+	if opts == nil {
+		opts = &RunOptions{}
+	}
+
+	var totalLines int
+	var totalBytes int64
+	var estimated time.Duration
+	if rs, ok := r.(io.ReadSeeker); ok {
+		var err error
+		totalLines, estimated, totalBytes, err = scanGCode(rs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan G-code: %w", err)
+		}
+	}
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	defer c.Disconnect()
+
+	tracker := newProgressTracker(totalLines, totalBytes, estimated, opts.OnProgress)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Poll status if requested. The send loop stays the connection's only
+	// reader and hands the reports it receives to onStatus.
+	var onStatus func(*FluidNCStatus)
+	if opts.Monitor {
+		intervals := make(chan time.Duration, 1)
+		go c.pollStatus(runCtx, intervals)
+
+		interval := c.config.StatusInterval
+		onStatus = func(status *FluidNCStatus) {
+			if next := c.statusInterval(status.State); next != interval {
+				interval = next
+				// Replace any interval the poller has not picked up yet
+				select {
+				case <-intervals:
+				default:
+				}
+				intervals <- next
+			}
+
+			if opts.OnProgress != nil {
+				tracker.statusUpdate(status)
+			}
+			if opts.OnStatus != nil {
+				opts.OnStatus(status)
+			}
+		}
+	}
+
+	// Abort the machine if the caller cancels before the job finishes
+	done := make(chan struct{})
+	aborted := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(aborted)
+		select {
+		case <-ctx.Done():
+			// The job context is already done, so the abort must not inherit it
+			if err := c.Abort(context.WithoutCancel(ctx)); err != nil {
+				c.logger.Warn("failed to abort job", "error", err)
+			}
+			c.interruptRead()
+		case <-done:
+		}
+	}()
+
+	stopped := func() (*JobSummary, error) {
+		<-aborted
+		return tracker.summary(opts.Name, false), fmt.Errorf("job aborted: %w", ctx.Err())
+	}
+
+	estimator := newGCodeEstimator()
+	var estimatedDone time.Duration
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return stopped()
+		}
+
+		lineBytes := int64(len(scanner.Bytes()) + 1)
+		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+
+		// Skip empty lines and comments
+		if !isSendableLine(line) {
+			tracker.skip(lineBytes)
+			continue
+		}
+
+		c.logger.Debug("sending line", "line", lineNum, "gcode", line)
+
+		estimatedDone += estimator.Estimate(line)
+		tracker.lineSent(lineBytes, estimator.Feed())
+
+		response, err := c.sendCommand(ctx, line, onStatus)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stopped()
+			}
+			return tracker.summary(opts.Name, false), fmt.Errorf("error on line %d: %w", lineNum, err)
+		}
+
+		// Check for errors
+		if strings.Contains(strings.ToLower(response), "error") {
+			return tracker.summary(opts.Name, false), fmt.Errorf("FluidNC error on line %d: %s", lineNum, response)
+		}
+
+		tracker.lineAcked(estimatedDone)
+
+		c.logger.Debug("line acknowledged", "line", lineNum, "response", response)
+
+		// Small delay between commands
+		if c.config.CommandDelay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(c.config.CommandDelay):
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return tracker.summary(opts.Name, false), err
+	}
+
+	return tracker.summary(opts.Name, true), nil
+}
+
+// pollStatus requests a status report every interval until ctx is done,
+// switching to each interval received on next. It only writes; the replies are
+// read by whoever is reading the connection.
+func (c *Client) pollStatus(ctx context.Context, next <-chan time.Duration) {
+	interval := c.config.StatusInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-next:
+			if d != interval {
+				interval = d
+				ticker.Reset(interval)
+			}
+		case <-ticker.C:
+			if err := c.SendRealTimeCommand(ctx, '?'); err != nil {
+				c.logger.Debug("status request failed", "error", err)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package fluidnc
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -8,14 +9,14 @@ import (
 )
 
 // SendHTTPCommand sends a command via HTTP POST to /command endpoint
-func (c *Client) SendHTTPCommand(command string, silent bool) (string, error) {
+func (c *Client) SendHTTPCommand(ctx context.Context, command string, silent bool) (string, error) {
 	endpoint := "command"
 	if silent {
 		endpoint = "command_silent"
 	}
 
 	url := fmt.Sprintf("http://%s:%d/%s", c.config.Host, c.config.Port, endpoint)
-	resp, err := http.Post(url, "text/plain", strings.NewReader(command))
+	resp, err := c.post(ctx, url, "text/plain", command)
 	if err != nil {
 		return "", fmt.Errorf("failed to send HTTP command: %w", err)
 	}
@@ -35,9 +36,9 @@ func (c *Client) SendHTTPCommand(command string, silent bool) (string, error) {
 }
 
 // HTTPFeedHold sends feed hold via HTTP
-func (c *Client) HTTPFeedHold() error {
+func (c *Client) HTTPFeedHold(ctx context.Context) error {
 	url := fmt.Sprintf("http://%s:%d/feedhold_reload", c.config.Host, c.config.Port)
-	resp, err := http.Post(url, "application/json", nil)
+	resp, err := c.post(ctx, url, "application/json", "")
 	if err != nil {
 		return fmt.Errorf("failed to send feed hold: %w", err)
 	}
@@ -52,9 +53,9 @@ func (c *Client) HTTPFeedHold() error {
 }
 
 // HTTPCycleStart sends cycle start via HTTP
-func (c *Client) HTTPCycleStart() error {
+func (c *Client) HTTPCycleStart(ctx context.Context) error {
 	url := fmt.Sprintf("http://%s:%d/cyclestart_reload", c.config.Host, c.config.Port)
-	resp, err := http.Post(url, "application/json", nil)
+	resp, err := c.post(ctx, url, "application/json", "")
 	if err != nil {
 		return fmt.Errorf("failed to send cycle start: %w", err)
 	}
@@ -69,9 +70,9 @@ func (c *Client) HTTPCycleStart() error {
 }
 
 // HTTPRestart sends restart command via HTTP
-func (c *Client) HTTPRestart() error {
+func (c *Client) HTTPRestart(ctx context.Context) error {
 	url := fmt.Sprintf("http://%s:%d/restart_reload", c.config.Host, c.config.Port)
-	resp, err := http.Post(url, "application/json", nil)
+	resp, err := c.post(ctx, url, "application/json", "")
 	if err != nil {
 		return fmt.Errorf("failed to send restart: %w", err)
 	}
@@ -86,9 +87,9 @@ func (c *Client) HTTPRestart() error {
 }
 
 // CheckDidRestart checks if a restart has occurred
-func (c *Client) CheckDidRestart() (bool, error) {
+func (c *Client) CheckDidRestart(ctx context.Context) (bool, error) {
 	url := fmt.Sprintf("http://%s:%d/did_restart", c.config.Host, c.config.Port)
-	resp, err := c.client.Get(url)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return false, fmt.Errorf("failed to check restart status: %w", err)
 	}
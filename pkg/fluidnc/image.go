@@ -0,0 +1,203 @@
+package fluidnc
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// esp32ImageMagic is the first byte of every ESP32 application image
+	esp32ImageMagic = 0xE9
+
+	// esp32HeaderSize is the size of the image header including the extended header
+	esp32HeaderSize = 24
+
+	// esp32MaxSegments is the most segments the ROM bootloader accepts
+	esp32MaxSegments = 16
+
+	// DefaultOTAPartitionSize is the app partition size of FluidNC's default partition table
+	DefaultOTAPartitionSize = 0x1E0000
+)
+
+// esp32ChipNames maps image header chip IDs to chip names
+var esp32ChipNames = map[uint16]string{
+	0:  "ESP32",
+	2:  "ESP32-S2",
+	5:  "ESP32-C3",
+	9:  "ESP32-S3",
+	12: "ESP32-C2",
+	13: "ESP32-C6",
+}
+
+// ValidateFirmwareImage checks that a file is a well formed ESP32 application
+// image that fits the OTA partition and, if requested, matches a SHA256 checksum
+// given directly or looked up in a release manifest
+func ValidateFirmwareImage(firmwarePath string, opts *FirmwareValidationOptions) (*FirmwareImageInfo, error) {
+	if opts == nil {
+		opts = &FirmwareValidationOptions{}
+	}
+
+	data, err := os.ReadFile(firmwarePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read firmware file: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	info := &FirmwareImageInfo{
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+
+	if err := parseESP32Image(data, info); err != nil {
+		return info, err
+	}
+
+	maxSize := opts.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultOTAPartitionSize
+	}
+	if info.Size > maxSize {
+		return info, fmt.Errorf("image is %d bytes, larger than the %d byte OTA partition", info.Size, maxSize)
+	}
+
+	expected := strings.ToLower(strings.TrimSpace(opts.SHA256))
+	if expected == "" && opts.ManifestPath != "" {
+		expected, err = manifestSHA256(opts.ManifestPath, filepath.Base(firmwarePath))
+		if err != nil {
+			return info, err
+		}
+	}
+	if expected != "" {
+		if expected != info.SHA256 {
+			return info, fmt.Errorf("SHA256 mismatch: image is %s, expected %s", info.SHA256, expected)
+		}
+		info.ChecksumVerified = true
+	}
+
+	return info, nil
+}
+
+// parseESP32Image validates the ESP32 image header, segment checksum and any appended hash
+func parseESP32Image(data []byte, info *FirmwareImageInfo) error {
+	if len(data) < esp32HeaderSize {
+		return fmt.Errorf("image is too small to be an ESP32 firmware image (%d bytes)", len(data))
+	}
+	if data[0] != esp32ImageMagic {
+		return fmt.Errorf("not an ESP32 firmware image: magic byte is 0x%02X, expected 0x%02X", data[0], esp32ImageMagic)
+	}
+
+	info.Segments = int(data[1])
+	if info.Segments == 0 || info.Segments > esp32MaxSegments {
+		return fmt.Errorf("invalid segment count %d in image header", info.Segments)
+	}
+
+	info.ChipID = binary.LittleEndian.Uint16(data[12:14])
+	info.ChipName = esp32ChipNames[info.ChipID]
+	if info.ChipName == "" {
+		info.ChipName = fmt.Sprintf("unknown (%d)", info.ChipID)
+	}
+	info.HashAppended = data[23] == 1
+
+	// Walk the segments, accumulating the XOR checksum of their contents
+	pos := esp32HeaderSize
+	checksum := byte(0xEF)
+	for i := 0; i < info.Segments; i++ {
+		if pos+8 > len(data) {
+			return fmt.Errorf("image truncated in segment %d header", i)
+		}
+		length := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		pos += 8
+		if length < 0 || pos+length > len(data) {
+			return fmt.Errorf("image truncated in segment %d (%d bytes declared)", i, length)
+		}
+		for _, b := range data[pos : pos+length] {
+			checksum ^= b
+		}
+		pos += length
+	}
+
+	// The checksum byte ends the padding to the next 16 byte boundary
+	pos = (pos + 16) &^ 15
+	if pos > len(data) {
+		return fmt.Errorf("image truncated before checksum")
+	}
+	if data[pos-1] != checksum {
+		return fmt.Errorf("image checksum mismatch: stored 0x%02X, computed 0x%02X", data[pos-1], checksum)
+	}
+
+	if info.HashAppended {
+		if pos+sha256.Size > len(data) {
+			return fmt.Errorf("image truncated before appended SHA256")
+		}
+		digest := sha256.Sum256(data[:pos])
+		if !bytes.Equal(digest[:], data[pos:pos+sha256.Size]) {
+			return fmt.Errorf("image appended SHA256 does not match its contents")
+		}
+	}
+
+	return nil
+}
+
+// manifestSHA256 finds the checksum for name in a sha256sum style text file or a JSON release manifest
+func manifestSHA256(manifestPath, name string) (string, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if json.Valid(data) {
+		var manifest any
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return "", fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if sum := findManifestSHA256(manifest, name); sum != "" {
+			return strings.ToLower(sum), nil
+		}
+		return "", fmt.Errorf("no SHA256 for %s in manifest %s", name, manifestPath)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && filepath.Base(strings.TrimPrefix(fields[1], "*")) == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+
+	return "", fmt.Errorf("no SHA256 for %s in manifest %s", name, manifestPath)
+}
+
+// findManifestSHA256 searches a decoded JSON manifest for an object naming the
+// file (in any string field) that also carries a "sha256" field
+func findManifestSHA256(node any, name string) string {
+	switch v := node.(type) {
+	case map[string]any:
+		if sum, ok := v["sha256"].(string); ok {
+			for key, value := range v {
+				if s, ok := value.(string); ok && key != "sha256" && filepath.Base(s) == name {
+					return sum
+				}
+			}
+		}
+		for _, value := range v {
+			if sum := findManifestSHA256(value, name); sum != "" {
+				return sum
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if sum := findManifestSHA256(value, name); sum != "" {
+				return sum
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ClientInterface defines the interface for FluidNC client operations
+type ClientInterface interface {
+	// Connection management
+	Connect(ctx context.Context) error
+	Disconnect() error
+	Login(ctx context.Context) error
+
+	// Command operations (over the configured transport)
+	SendCommand(ctx context.Context, command string) (string, error)
+	SendRealTimeCommand(ctx context.Context, command byte) error
+
+	// HTTP operations
+	SendHTTPCommand(ctx context.Context, command string, silent bool) (string, error)
+
+	// Status and monitoring
+	GetStatus(ctx context.Context) (*FluidNCStatus, error)
+	ParseStatus(response string) *FluidNCStatus
+	MonitorStatus(ctx context.Context, callback func(*FluidNCStatus)) error
+	Subscribe(ctx context.Context, filter EventFilter) <-chan Event
+
+	// Control operations
+	FeedHold(ctx context.Context) error
+	CycleStart(ctx context.Context) error
+	SoftReset(ctx context.Context) error
+	SpindleStop(ctx context.Context) error
+	Abort(ctx context.Context) error
+	Home(ctx context.Context) error
+	Unlock(ctx context.Context) error
+
+	// HTTP control operations
+	HTTPFeedHold(ctx context.Context) error
+	HTTPCycleStart(ctx context.Context) error
+	HTTPRestart(ctx context.Context) error
+	CheckDidRestart(ctx context.Context) (bool, error)
+
+	// File operations
+	ListFiles(ctx context.Context, remotePath string, sd bool) (*FileListResponse, error)
+	ListFilesRecursive(ctx context.Context, remotePath string, sd bool) (*FileListResponse, error)
+	UploadFile(ctx context.Context, filePath, destination, endpoint string) error
+	UploadFileWithProgress(ctx context.Context, filePath, destination, endpoint string, onProgress func(*TransferProgress)) error
+	VerifyUpload(ctx context.Context, filePath, destination string, sd bool) (*VerifyResult, error)
+	UploadToLocalFS(ctx context.Context, filePath string, destinationName string) error
+	UploadToSD(ctx context.Context, filePath string, destinationName string) error
+	DeleteFile(ctx context.Context, remotePath string, sd bool) error
+	RenameFile(ctx context.Context, remotePath, newName string, sd bool) error
+	MakeDir(ctx context.Context, remotePath string, sd bool) error
+	RemoveDir(ctx context.Context, remotePath string, sd bool) error
+	PlanSync(ctx context.Context, localDir, remoteDir string, sd bool, opts *SyncOptions) (*SyncPlan, error)
+	ApplySync(ctx context.Context, plan *SyncPlan, onProgress func(*TransferProgress)) error
+	DownloadFile(ctx context.Context, remotePath string, w io.Writer) error
+	DownloadFileWithProgress(ctx context.Context, remotePath string, w io.Writer, onProgress func(*TransferProgress)) error
+	UpdateFirmware(ctx context.Context, firmwarePath string) error
+	UpdateFirmwareWithProgress(ctx context.Context, firmwarePath string, onProgress func(*TransferProgress)) error
+	UpdateFirmwareAndVerify(ctx context.Context, firmwarePath string, opts *FirmwareUpdateOptions) (*FirmwareUpdateResult, error)
+	WaitForRestart(ctx context.Context) error
+	BackupConfig(ctx context.Context, localPath string) error
+
+	// Information
+	GetAlarms(ctx context.Context) ([]AlarmInfo, error)
+	GetSettings(ctx context.Context) (string, error)
+	GetSetting(ctx context.Context, name string) (string, error)
+	GetCommands(ctx context.Context) (string, error)
+	GetVersion(ctx context.Context) (string, error)
+	FirmwareVersion(ctx context.Context) (string, error)
+	ConfigFilename(ctx context.Context) (string, error)
+
+	// G-code execution
+	RunGCodeFile(ctx context.Context, filePath string, opts *RunOptions) (*JobSummary, error)
+	RunGCode(ctx context.Context, r io.Reader, opts *RunOptions) (*JobSummary, error)
+	RunRemoteFile(ctx context.Context, remotePath string, opts *RunOptions) (*JobSummary, error)
+}
+
+// Validate that Client implements ClientInterface
+var _ ClientInterface = (*Client)(nil)
+
+// ClientOptions provides options for creating a new client
+type ClientOptions struct {
+	Config         *Config
+	HTTPClient     *http.Client // Copied; given the client's cookie jar when its Jar is nil
+	ConnectTimeout time.Duration
+	RetryAttempts  int
+	RetryDelay     time.Duration
+	Logger         *slog.Logger // Receives diagnostics; discarded when nil
+	Trace          *slog.Logger // Receives every frame and HTTP exchange; tracing is off when nil
+}
+
+// NewClientWithOptions creates a new FluidNC client with custom options
+func NewClientWithOptions(opts *ClientOptions) *Client {
+	client := NewClient(opts.Config)
+	if opts.HTTPClient != nil {
+		// Login cookies need a jar; copy so the caller's client is left untouched
+		httpClient := *opts.HTTPClient
+		if httpClient.Jar == nil {
+			httpClient.Jar = client.client.Jar
+		}
+		client.client = &httpClient
+	}
+	if opts.Logger != nil {
+		client.logger = opts.Logger
+	}
+	if opts.Trace != nil {
+		client.trace = opts.Trace
+		client.client = tracedHTTPClient(client.client, opts.Trace)
+	}
+
+	return client
+}
+
+// IsConnected returns true if a transport connection is active
+func (c *Client) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn != nil
+}
+
+// GetConfig returns the client configuration
+func (c *Client) GetConfig() *Config {
+	return c.config
+}
+
+// Ping sends a ping to test connection
+func (c *Client) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("http://%s:%d/", c.config.Host, c.config.Port)
+	resp, err := c.get(ctx, url)
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ping failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
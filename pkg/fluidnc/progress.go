@@ -0,0 +1,318 @@
+package fluidnc
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRapidRate is the assumed G0 feed rate in mm/min used for time estimates
+const defaultRapidRate = 5000.0
+
+// gcodeEstimator estimates machining time by tracking modal G-code state
+type gcodeEstimator struct {
+	motion   int
+	absolute bool
+	inches   bool
+	feed     float64
+	pos      [3]float64
+}
+
+// newGCodeEstimator creates an estimator in the default power-on modal state
+func newGCodeEstimator() *gcodeEstimator {
+	return &gcodeEstimator{
+		motion:   0,
+		absolute: true,
+	}
+}
+
+// stripComment removes parenthesised and semicolon comments from a G-code line
+func stripComment(line string) string {
+	if idx := strings.Index(line, ";"); idx >= 0 {
+		line = line[:idx]
+	}
+	for {
+		start := strings.Index(line, "(")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(line[start:], ")")
+		if end < 0 {
+			line = line[:start]
+			break
+		}
+		line = line[:start] + line[start+end+1:]
+	}
+	return strings.TrimSpace(line)
+}
+
+// parseWords splits a G-code line into letter/value pairs
+func parseWords(line string) map[byte][]float64 {
+	words := make(map[byte][]float64)
+	line = strings.ToUpper(strings.ReplaceAll(line, " ", ""))
+
+	for i := 0; i < len(line); {
+		letter := line[i]
+		if letter < 'A' || letter > 'Z' {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(line) && (line[j] == '.' || line[j] == '-' || line[j] == '+' || (line[j] >= '0' && line[j] <= '9')) {
+			j++
+		}
+		if value, err := strconv.ParseFloat(line[i+1:j], 64); err == nil {
+			words[letter] = append(words[letter], value)
+		}
+		i = j
+	}
+
+	return words
+}
+
+// Feed returns the programmed feed rate in mm/min
+func (e *gcodeEstimator) Feed() float64 {
+	return e.feed
+}
+
+// Estimate updates modal state for a line and returns its estimated duration
+func (e *gcodeEstimator) Estimate(line string) time.Duration {
+	words := parseWords(stripComment(line))
+	if len(words) == 0 {
+		return 0
+	}
+
+	scale := 1.0
+	dwell := 0.0
+	for _, g := range words['G'] {
+		switch g {
+		case 0, 1, 2, 3:
+			e.motion = int(g)
+		case 4:
+			if p, ok := words['P']; ok {
+				dwell = p[0]
+			}
+		case 20:
+			e.inches = true
+		case 21:
+			e.inches = false
+		case 90:
+			e.absolute = true
+		case 91:
+			e.absolute = false
+		}
+	}
+	if e.inches {
+		scale = 25.4
+	}
+
+	if f, ok := words['F']; ok {
+		e.feed = f[0] * scale
+	}
+
+	if dwell > 0 {
+		return time.Duration(dwell * float64(time.Second))
+	}
+
+	target := e.pos
+	moved := false
+	for axis, letter := range []byte{'X', 'Y', 'Z'} {
+		if v, ok := words[letter]; ok {
+			moved = true
+			if e.absolute {
+				target[axis] = v[0] * scale
+			} else {
+				target[axis] += v[0] * scale
+			}
+		}
+	}
+	if !moved {
+		return 0
+	}
+
+	var distance float64
+	switch e.motion {
+	case 2, 3:
+		distance = e.arcLength(target, words, scale)
+	default:
+		dx, dy, dz := target[0]-e.pos[0], target[1]-e.pos[1], target[2]-e.pos[2]
+		distance = math.Sqrt(dx*dx + dy*dy + dz*dz)
+	}
+	e.pos = target
+
+	rate := e.feed
+	if e.motion == 0 || rate <= 0 {
+		rate = defaultRapidRate
+	}
+
+	return time.Duration(distance / rate * float64(time.Minute))
+}
+
+// arcLength approximates the length of a G2/G3 arc in the XY plane
+func (e *gcodeEstimator) arcLength(target [3]float64, words map[byte][]float64, scale float64) float64 {
+	dz := target[2] - e.pos[2]
+	chord := math.Hypot(target[0]-e.pos[0], target[1]-e.pos[1])
+
+	var radius, angle float64
+	if r, ok := words['R']; ok {
+		radius = math.Abs(r[0] * scale)
+		if radius == 0 || chord > 2*radius {
+			return math.Hypot(chord, dz)
+		}
+		angle = 2 * math.Asin(chord/(2*radius))
+		if r[0] < 0 {
+			angle = 2*math.Pi - angle
+		}
+	} else {
+		var i, j float64
+		if v, ok := words['I']; ok {
+			i = v[0] * scale
+		}
+		if v, ok := words['J']; ok {
+			j = v[0] * scale
+		}
+		cx, cy := e.pos[0]+i, e.pos[1]+j
+		radius = math.Hypot(i, j)
+		if radius == 0 {
+			return math.Hypot(chord, dz)
+		}
+
+		start := math.Atan2(e.pos[1]-cy, e.pos[0]-cx)
+		end := math.Atan2(target[1]-cy, target[0]-cx)
+		if e.motion == 2 {
+			angle = start - end
+		} else {
+			angle = end - start
+		}
+		if angle <= 0 {
+			angle += 2 * math.Pi
+		}
+	}
+
+	return math.Hypot(radius*angle, dz)
+}
+
+// progressTracker accumulates job progress while a file is streamed
+type progressTracker struct {
+	mu       sync.Mutex
+	progress JobProgress
+	skipped  int64 // Bytes of comments and blank lines, read but never sent
+	started  time.Time
+	callback func(*JobProgress)
+}
+
+// newProgressTracker creates a tracker for a job with known totals
+func newProgressTracker(totalLines int, totalBytes int64, estimated time.Duration, callback func(*JobProgress)) *progressTracker {
+	return &progressTracker{
+		progress: JobProgress{
+			TotalLines:     totalLines,
+			TotalBytes:     totalBytes,
+			EstimatedTotal: estimated,
+		},
+		started:  time.Now(),
+		callback: callback,
+	}
+}
+
+// lineSent records a line being transmitted to the controller
+func (t *progressTracker) lineSent(bytes int64, programmedFeed float64) {
+	t.mu.Lock()
+	t.progress.LinesSent++
+	t.progress.BytesSent += bytes
+	t.progress.ProgrammedFeed = programmedFeed
+	t.mu.Unlock()
+}
+
+// lineAcked records a controller acknowledgement and reports progress
+func (t *progressTracker) lineAcked(estimatedDone time.Duration) {
+	t.mu.Lock()
+	t.progress.LinesAcked++
+	t.progress.EstimatedDone = estimatedDone
+	t.mu.Unlock()
+	t.report()
+}
+
+// skip records bytes consumed by lines that are not sent. They count towards
+// the percentage of the file done but not towards BytesSent.
+func (t *progressTracker) skip(bytes int64) {
+	t.mu.Lock()
+	t.skipped += bytes
+	t.mu.Unlock()
+}
+
+// statusUpdate records the live machine state and reports progress
+func (t *progressTracker) statusUpdate(status *FluidNCStatus) {
+	t.mu.Lock()
+	t.progress.State = status.State
+	t.progress.CurrentFeed = status.FeedRate
+	t.mu.Unlock()
+	t.report()
+}
+
+// remoteUpdate records progress of a job executed by the controller itself
+func (t *progressTracker) remoteUpdate(status *FluidNCStatus) {
+	t.mu.Lock()
+	t.progress.State = status.State
+	t.progress.CurrentFeed = status.FeedRate
+	if status.SDFile != "" {
+		t.progress.PercentBytes = status.SDProgress
+	}
+	t.mu.Unlock()
+	t.report()
+}
+
+// snapshot returns a copy of the current progress with derived fields filled in
+func (t *progressTracker) snapshot() *JobProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.progress
+	p.Elapsed = time.Since(t.started)
+
+	if done := p.BytesSent + t.skipped; p.TotalBytes > 0 && done > 0 {
+		p.PercentBytes = float64(done) / float64(p.TotalBytes) * 100
+	}
+	if p.EstimatedTotal > 0 {
+		p.PercentTime = float64(p.EstimatedDone) / float64(p.EstimatedTotal) * 100
+	}
+
+	// Calibrate the remaining time against actual elapsed time once under way,
+	// falling back to byte progress when no time estimate is available
+	percent := p.PercentTime
+	if p.EstimatedTotal == 0 {
+		percent = p.PercentBytes
+	}
+	switch {
+	case percent > 0 && percent < 100:
+		p.Remaining = time.Duration(float64(p.Elapsed) * (100 - percent) / percent)
+	case percent == 0:
+		p.Remaining = p.EstimatedTotal
+	}
+
+	return &p
+}
+
+// report invokes the progress callback with a fresh snapshot
+func (t *progressTracker) report() {
+	if t.callback != nil {
+		t.callback(t.snapshot())
+	}
+}
+
+// summary builds the final job summary
+func (t *progressTracker) summary(file string, completed bool) *JobSummary {
+	p := t.snapshot()
+	return &JobSummary{
+		File:           file,
+		Completed:      completed,
+		LinesSent:      p.LinesSent,
+		LinesAcked:     p.LinesAcked,
+		TotalLines:     p.TotalLines,
+		BytesSent:      p.BytesSent,
+		TotalBytes:     p.TotalBytes,
+		Elapsed:        p.Elapsed,
+		EstimatedTotal: p.EstimatedTotal,
+	}
+}
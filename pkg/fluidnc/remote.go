@@ -0,0 +1,165 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// remoteStartTimeout is how long a started job may take to appear in status
+// reports before it is taken to have failed to start
+const remoteStartTimeout = 10 * time.Second
+
+// remoteRunCommand builds the FluidNC command that executes a stored file,
+// resolved by remoteStoragePath
+func remoteRunCommand(remotePath string) string {
+	sd, filePath := remoteStoragePath(remotePath)
+	if sd {
+		return "$SD/Run=" + filePath
+	}
+	return "$LocalFS/Run=" + filePath
+}
+
+// readLines forwards each line received on the connection until it fails or
+// ctx is done. The caller holds readMu until lines is closed.
+func (c *Client) readLines(ctx context.Context, conn Transport, lines chan<- string) {
+	defer close(lines)
+
+	for {
+		line, err := c.readLine(ctx, conn)
+		if err != nil {
+			return
+		}
+
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunRemoteFile starts execution of a file stored on the controller, e.g.
+// /sd/job.nc or /localfs/job.nc, and polls its status until completion,
+// passing each report to opts.OnStatus and opts.OnProgress. Cancelling the
+// context performs a safe abort of the running job.
+func (c *Client) RunRemoteFile(ctx context.Context, remotePath string, opts *RunOptions) (*JobSummary, error) {
+	if opts == nil {
+		opts = &RunOptions{}
+	}
+	name := opts.Name
+	if name == "" {
+		name = remotePath
+	}
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	defer c.Disconnect()
+
+	response, err := c.SendCommand(ctx, remoteRunCommand(remotePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", remotePath, err)
+	}
+	if strings.Contains(strings.ToLower(response), "error") {
+		return nil, fmt.Errorf("FluidNC refused to run %s: %s", remotePath, response)
+	}
+
+	tracker := newProgressTracker(0, 0, 0, opts.OnProgress)
+
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	// This loop is the connection's only reader until the job ends
+	readCtx, stopReading := context.WithCancel(ctx)
+	lines := make(chan string, 16)
+	c.readMu.Lock()
+	go c.readLines(readCtx, conn, lines)
+	defer func() {
+		stopReading()
+		for range lines {
+		}
+		c.readMu.Unlock()
+	}()
+
+	aborted := func() (*JobSummary, error) {
+		// The job context is already done, so the abort must not inherit it
+		if err := c.Abort(context.WithoutCancel(ctx)); err != nil {
+			c.logger.Warn("failed to abort job", "error", err)
+		}
+		return tracker.summary(name, false), fmt.Errorf("job aborted: %w", ctx.Err())
+	}
+
+	ticker := time.NewTicker(c.config.StatusInterval)
+	defer ticker.Stop()
+	interval := c.config.StatusInterval
+
+	// Idle only means the job finished once its file has been reported
+	seenFile := false
+	startTimer := time.NewTimer(remoteStartTimeout)
+	defer startTimer.Stop()
+	startTimeout := startTimer.C
+
+	for {
+		select {
+		case <-ctx.Done():
+			return aborted()
+
+		case <-startTimeout:
+			return tracker.summary(name, false), fmt.Errorf("%s did not start within %s", remotePath, remoteStartTimeout)
+
+		case <-ticker.C:
+			if err := c.SendRealTimeCommand(ctx, '?'); err != nil {
+				c.logger.Debug("status request failed", "error", err)
+			}
+
+		case line, ok := <-lines:
+			if !ok {
+				if ctx.Err() != nil {
+					return aborted()
+				}
+				return tracker.summary(name, false), fmt.Errorf("connection closed while running %s", remotePath)
+			}
+
+			switch {
+			case strings.HasPrefix(line, "<"):
+				status := c.ParseStatus(line)
+				tracker.remoteUpdate(status)
+				if opts.OnStatus != nil {
+					opts.OnStatus(status)
+				}
+
+				if next := c.statusInterval(status.State); next != interval {
+					interval = next
+					ticker.Reset(interval)
+				}
+
+				if status.SDFile != "" {
+					seenFile = true
+					startTimeout = nil
+					continue
+				}
+				if strings.HasPrefix(status.State, "Alarm") {
+					return tracker.summary(name, false), fmt.Errorf("machine entered alarm state while running %s", remotePath)
+				}
+				if status.State == "Idle" && seenFile {
+					return tracker.summary(name, true), nil
+				}
+
+			case strings.HasPrefix(line, "[MSG:"):
+				message := strings.TrimSuffix(strings.TrimPrefix(line, "[MSG:"), "]")
+				if opts.OnMessage != nil {
+					opts.OnMessage(message)
+				}
+				if strings.Contains(message, "job succeeded") {
+					return tracker.summary(name, true), nil
+				}
+
+			case c.errorRegex.MatchString(line), c.alarmRegex.MatchString(line):
+				return tracker.summary(name, false), fmt.Errorf("FluidNC reported %s while running %s", line, remotePath)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// serialResetPulse is how long EN is held low when resetting via RTS
+	serialResetPulse = 100 * time.Millisecond
+
+	// serialDrainPeriod is how long pending boot output is discarded when not resetting
+	serialDrainPeriod = 200 * time.Millisecond
+)
+
+// dialSerial opens a USB serial connection to the controller. When reset is
+// set, the ESP32 is restarted via the DTR/RTS auto-reset circuit, or with a
+// soft reset where the port has no modem control lines, and the FluidNC
+// welcome banner must appear within timeout.
+func dialSerial(ctx context.Context, port string, baud int, reset bool, timeout time.Duration) (Transport, error) {
+	if port == "" {
+		return nil, fmt.Errorf("serial transport requires serial_port")
+	}
+
+	transport, err := openSerialPort(port, baud)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open serial port %s: %w", port, err)
+	}
+
+	// Release both lines so the auto-reset circuit lets the ESP32 run normally.
+	// Pseudo-terminals and some adapters have no modem control lines.
+	modemLines := transport.setModemLines(false, false) == nil
+
+	if reset {
+		if modemLines && transport.setModemLines(false, true) == nil {
+			time.Sleep(serialResetPulse)
+			transport.setModemLines(false, false)
+		} else {
+			// A soft reset (Ctrl-X) also makes FluidNC print its banner
+			if err := transport.Write([]byte{0x18}); err != nil {
+				transport.Close()
+				return nil, fmt.Errorf("failed to reset controller: %w", err)
+			}
+		}
+
+		if _, err := waitForBanner(ctx, transport.streamTransport, timeout); err != nil {
+			transport.Close()
+			return nil, err
+		}
+		return transport, nil
+	}
+
+	// Discard any boot or log output already queued
+	transport.SetReadDeadline(time.Now().Add(serialDrainPeriod))
+	for {
+		if _, err := transport.readLine(); err != nil {
+			break
+		}
+	}
+	transport.SetReadDeadline(time.Time{})
+
+	return transport, nil
+}
+
+// waitForBanner reads lines until the FluidNC/Grbl welcome banner appears
+func waitForBanner(ctx context.Context, t *streamTransport, timeout time.Duration) (string, error) {
+	if err := t.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return "", err
+	}
+	defer t.SetReadDeadline(time.Time{})
+
+	stop := context.AfterFunc(ctx, func() {
+		t.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	for {
+		line, err := t.readLine()
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", fmt.Errorf("no FluidNC welcome banner received: %w", err)
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "Grbl") {
+			return line, nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+//go:build linux
+
+package fluidnc
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// serialBaudRates maps supported baud rates to termios speed constants
+var serialBaudRates = map[int]uint32{
+	9600:    unix.B9600,
+	19200:   unix.B19200,
+	38400:   unix.B38400,
+	57600:   unix.B57600,
+	115200:  unix.B115200,
+	230400:  unix.B230400,
+	460800:  unix.B460800,
+	921600:  unix.B921600,
+	1000000: unix.B1000000,
+	2000000: unix.B2000000,
+}
+
+// serialTransport is a stream transport over a tty with modem line control
+type serialTransport struct {
+	*streamTransport
+	file *os.File
+}
+
+// openSerialPort opens a tty in raw 8N1 mode at the given baud rate
+func openSerialPort(port string, baud int) (*serialTransport, error) {
+	speed, ok := serialBaudRates[baud]
+	if !ok {
+		return nil, fmt.Errorf("unsupported baud rate %d", baud)
+	}
+
+	// Non-blocking mode lets the runtime poller provide read deadlines
+	file, err := os.OpenFile(port, os.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = controlFile(file, func(fd int) error {
+		t, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+		if err != nil {
+			return err
+		}
+
+		t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON | unix.IXOFF
+		t.Oflag &^= unix.OPOST
+		t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		t.Cflag &^= unix.CSIZE | unix.PARENB | unix.CSTOPB | unix.CRTSCTS | unix.CBAUD
+		t.Cflag |= unix.CS8 | unix.CREAD | unix.CLOCAL | speed
+		t.Ispeed = speed
+		t.Ospeed = speed
+		t.Cc[unix.VMIN] = 1
+		t.Cc[unix.VTIME] = 0
+
+		return unix.IoctlSetTermios(fd, unix.TCSETS, t)
+	})
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to configure port: %w", err)
+	}
+
+	return &serialTransport{
+		streamTransport: newStreamTransport(file, file.SetReadDeadline, nil),
+		file:            file,
+	}, nil
+}
+
+// setModemLines asserts or releases the DTR and RTS lines
+func (t *serialTransport) setModemLines(dtr, rts bool) error {
+	return controlFile(t.file, func(fd int) error {
+		for _, line := range []struct {
+			bit int
+			on  bool
+		}{{unix.TIOCM_DTR, dtr}, {unix.TIOCM_RTS, rts}} {
+			req := uint(unix.TIOCMBIC)
+			if line.on {
+				req = unix.TIOCMBIS
+			}
+			if err := unix.IoctlSetPointerInt(fd, req, line.bit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// controlFile runs fn with the file descriptor without switching it to blocking mode
+func controlFile(file *os.File, fn func(fd int) error) error {
+	raw, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+	if err := raw.Control(func(fd uintptr) {
+		fnErr = fn(int(fd))
+	}); err != nil {
+		return err
+	}
+	return fnErr
+}
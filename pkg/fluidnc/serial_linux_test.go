@@ -0,0 +1,155 @@
+//go:build linux
+
+package fluidnc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPty opens a pseudo-terminal pair, returning the controller end and the
+// path of the tty the client opens
+func openPty(t *testing.T) (*os.File, string) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminals unavailable: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatalf("failed to unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Fatalf("failed to get pty number: %v", err)
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+// serialClient creates a client for the tty at path
+func serialClient(path string, reset bool) *Client {
+	return NewClient(&Config{
+		Transport:   "serial",
+		SerialPort:  path,
+		Baud:        115200,
+		SerialReset: reset,
+		Timeout:     2 * time.Second,
+	})
+}
+
+// connectSerial connects a client to the tty at path without resetting the controller
+func connectSerial(t *testing.T, path string) *Client {
+	t.Helper()
+
+	client := serialClient(path, false)
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+	return client
+}
+
+func TestSerialSendCommand(t *testing.T) {
+	master, path := openPty(t)
+	client := connectSerial(t, path)
+
+	// Answer $I like a controller, splitting the reply across writes
+	received := make(chan string, 1)
+	go func() {
+		line, err := bufio.NewReader(master).ReadString('\n')
+		if err != nil {
+			return
+		}
+		received <- strings.TrimSpace(line)
+		master.WriteString("[VER:3.7.8 FluidNC v3.7.8:]\n")
+		time.Sleep(20 * time.Millisecond)
+		master.WriteString("ok\n")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	response, err := client.SendCommand(ctx, "$I")
+	if err != nil {
+		t.Fatalf("SendCommand: %v", err)
+	}
+	if got := <-received; got != "$I" {
+		t.Errorf("controller received %q, want %q", got, "$I")
+	}
+	if want := "[VER:3.7.8 FluidNC v3.7.8:]\nok"; response != want {
+		t.Errorf("response = %q, want %q", response, want)
+	}
+}
+
+func TestSerialReadCancelled(t *testing.T) {
+	_, path := openPty(t)
+	client := connectSerial(t, path)
+
+	// The controller never answers, so only cancellation ends the read
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.SendCommand(ctx, "$I")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendCommand error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled read took %s", elapsed)
+	}
+}
+
+func TestSerialResetWaitsForBanner(t *testing.T) {
+	master, path := openPty(t)
+	const bannerDelay = 300 * time.Millisecond
+
+	// A pty has no modem lines, so the client falls back to a soft reset;
+	// answer it with the welcome banner after a delay, as a rebooting controller would
+	reset := make(chan byte, 1)
+	go func() {
+		buf := make([]byte, 1)
+		if _, err := master.Read(buf); err != nil {
+			return
+		}
+		reset <- buf[0]
+		time.Sleep(bannerDelay)
+		master.WriteString("[MSG:INFO: FluidNC v3.7.8]\n")
+		master.WriteString("Grbl 3.7 [FluidNC v3.7.8 (noradio) '$' for help]\n")
+	}()
+
+	client := serialClient(path, true)
+	start := time.Now()
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Disconnect()
+
+	if elapsed := time.Since(start); elapsed < bannerDelay {
+		t.Errorf("Connect returned after %s, before the banner was sent", elapsed)
+	}
+	if got := <-reset; got != 0x18 {
+		t.Errorf("controller received %#x, want soft reset 0x18", got)
+	}
+}
+
+func TestSerialResetWithoutBanner(t *testing.T) {
+	_, path := openPty(t)
+
+	client := serialClient(path, true)
+	client.config.Timeout = 200 * time.Millisecond
+	if err := client.Connect(context.Background()); err == nil {
+		client.Disconnect()
+		t.Fatal("Connect succeeded without a welcome banner")
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package fluidnc
+
+import "fmt"
+
+// serialTransport is unavailable on this platform
+type serialTransport struct {
+	*streamTransport
+}
+
+// openSerialPort reports that serial ports are not supported on this platform
+func openSerialPort(port string, baud int) (*serialTransport, error) {
+	return nil, fmt.Errorf("serial transport is only supported on Linux")
+}
+
+// setModemLines is unsupported on this platform
+func (t *serialTransport) setModemLines(dtr, rts bool) error {
+	return fmt.Errorf("modem control is not supported on this platform")
+}
@@ -0,0 +1,190 @@
+package fluidnc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// maxSessionLine bounds one line of a session file
+const maxSessionLine = 1024 * 1024
+
+// SessionEntry is one event of a recorded session
+type SessionEntry struct {
+	Time  time.Time // When the event happened
+	Event Event
+}
+
+// sessionLine is the JSON form of a SessionEntry, one per line of a session file
+type sessionLine struct {
+	Time  time.Time       `json:"time"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+	Error string          `json:"error,omitempty"` // ConnectionEvent.Err, which has no JSON form
+}
+
+// MarshalJSON encodes the entry with its event tagged by kind
+func (e SessionEntry) MarshalJSON() ([]byte, error) {
+	if e.Event == nil {
+		return nil, errors.New("session entry has no event")
+	}
+
+	data, err := json.Marshal(e.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	line := sessionLine{Time: e.Time, Type: e.Event.Kind().String(), Event: data}
+	if conn, ok := e.Event.(ConnectionEvent); ok && conn.Err != nil {
+		line.Error = conn.Err.Error()
+	}
+	return json.Marshal(line)
+}
+
+// UnmarshalJSON decodes an entry, restoring the concrete event type from its kind
+func (e *SessionEntry) UnmarshalJSON(data []byte) error {
+	var line sessionLine
+	if err := json.Unmarshal(data, &line); err != nil {
+		return err
+	}
+
+	kind, err := ParseEventKinds([]string{line.Type})
+	if err != nil {
+		return err
+	}
+
+	var event Event
+	switch kind {
+	case EventStatus:
+		var status StatusEvent
+		err = json.Unmarshal(line.Event, &status)
+		event = status
+	case EventStateChanged:
+		var changed StateChanged
+		err = json.Unmarshal(line.Event, &changed)
+		event = changed
+	case EventAlarm:
+		var alarm AlarmEvent
+		err = json.Unmarshal(line.Event, &alarm)
+		event = alarm
+	case EventError:
+		var cmdErr ErrorEvent
+		err = json.Unmarshal(line.Event, &cmdErr)
+		event = cmdErr
+	case EventMessage:
+		var message MessageEvent
+		err = json.Unmarshal(line.Event, &message)
+		event = message
+	case EventProbe:
+		var probe ProbeEvent
+		err = json.Unmarshal(line.Event, &probe)
+		event = probe
+	case EventConnection:
+		var conn ConnectionEvent
+		err = json.Unmarshal(line.Event, &conn)
+		if line.Error != "" {
+			conn.Err = errors.New(line.Error)
+		}
+		event = conn
+	default:
+		return fmt.Errorf("event type %q is not a single kind", line.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", line.Type, err)
+	}
+
+	e.Time = line.Time
+	e.Event = event
+	return nil
+}
+
+// EventTime returns when an event happened
+func EventTime(event Event) time.Time {
+	switch e := event.(type) {
+	case StatusEvent:
+		if e.Status != nil {
+			return e.Status.Timestamp
+		}
+	case StateChanged:
+		return e.Timestamp
+	case AlarmEvent:
+		return e.Timestamp
+	case ErrorEvent:
+		return e.Timestamp
+	case MessageEvent:
+		return e.Timestamp
+	case ProbeEvent:
+		return e.Timestamp
+	case ConnectionEvent:
+		return e.Timestamp
+	}
+	return time.Time{}
+}
+
+// SessionRecorder writes events to a session file as JSON Lines. It is safe
+// for concurrent use.
+type SessionRecorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewSessionRecorder creates a recorder writing to w
+func NewSessionRecorder(w io.Writer) *SessionRecorder {
+	return &SessionRecorder{encoder: json.NewEncoder(w)}
+}
+
+// Record writes one event, timestamped with when it happened
+func (r *SessionRecorder) Record(event Event) error {
+	at := EventTime(event)
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.encoder.Encode(SessionEntry{Time: at, Event: event}); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", event.Kind(), err)
+	}
+	return nil
+}
+
+// SessionReader reads the entries of a session file in order
+type SessionReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewSessionReader creates a reader of the session in r
+func NewSessionReader(r io.Reader) *SessionReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSessionLine)
+	return &SessionReader{scanner: scanner}
+}
+
+// Next returns the next entry, or io.EOF once the session is exhausted. Blank
+// lines are skipped.
+func (r *SessionReader) Next() (SessionEntry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := r.scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		var entry SessionEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return SessionEntry{}, fmt.Errorf("session line %d: %w", r.line, err)
+		}
+		return entry, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return SessionEntry{}, fmt.Errorf("failed to read session: %w", err)
+	}
+	return SessionEntry{}, io.EOF
+}
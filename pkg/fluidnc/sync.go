@@ -0,0 +1,236 @@
+package fluidnc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// remoteTree lists a remote directory tree keyed by relative path. A missing
+// directory is reported as an empty tree with exists set to false.
+func (c *Client) remoteTree(ctx context.Context, remoteDir string, sd bool) (map[string]FileInfo, bool, error) {
+	tree := make(map[string]FileInfo)
+
+	listing, err := c.ListFilesRecursive(ctx, remoteDir, sd)
+	if err != nil {
+		if remoteDir == "/" {
+			return nil, false, err
+		}
+
+		// Distinguish a missing directory from a failed request via its parent
+		parent, name := splitRemotePath(remoteDir)
+		parentListing, parentErr := c.ListFiles(ctx, parent, sd)
+		if parentErr != nil {
+			return nil, false, err
+		}
+		for _, file := range parentListing.Files {
+			if file.Name == name {
+				return nil, false, err
+			}
+		}
+		return tree, false, nil
+	}
+
+	for _, file := range listing.Files {
+		tree[file.Name] = file
+	}
+	return tree, true, nil
+}
+
+// localTree walks a local directory tree keyed by slash separated relative path
+func localTree(localDir string) (map[string]FileInfo, error) {
+	tree := make(map[string]FileInfo)
+
+	err := filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		file := FileInfo{Name: filepath.ToSlash(rel), Type: "file", Size: info.Size(), Modified: info.ModTime()}
+		if d.IsDir() {
+			file.Type = "directory"
+			file.Size = 0
+		}
+		tree[file.Name] = file
+		return nil
+	})
+
+	return tree, err
+}
+
+// PlanSync compares a local directory with a remote one by name, size and,
+// where the controller lists it, modification time. A size the listing only
+// gives rounded, such as "1.50 MB", cannot show a small edit, so such files
+// are checksummed unless their modification time settles it, and uploaded when
+// the controller cannot serve them back. opts.Checksum checksums every file
+// whose size matches. Remote files missing locally are only scheduled for
+// deletion when opts.Delete is set.
+func (c *Client) PlanSync(ctx context.Context, localDir, remoteDir string, sd bool, opts *SyncOptions) (*SyncPlan, error) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+
+	remoteDir = "/" + strings.Trim(remoteDir, "/")
+	plan := &SyncPlan{LocalDir: localDir, RemoteDir: remoteDir, SD: sd}
+
+	local, err := localTree(localDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", localDir, err)
+	}
+
+	remote, exists, err := c.remoteTree(ctx, remoteDir, sd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", remoteDir, err)
+	}
+
+	if !exists {
+		plan.Actions = append(plan.Actions, SyncAction{Action: "mkdir", RemotePath: remoteDir, Reason: "missing"})
+	}
+
+	names := make([]string, 0, len(local))
+	for name := range local {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mkdirs, uploads, deletes, rmdirs []SyncAction
+	for _, name := range names {
+		file := local[name]
+		remotePath := path.Join(remoteDir, name)
+		existing, found := remote[name]
+
+		if file.Type == "directory" {
+			if !found {
+				mkdirs = append(mkdirs, SyncAction{Action: "mkdir", RemotePath: remotePath, Reason: "missing"})
+			}
+			continue
+		}
+
+		action := SyncAction{
+			Action:     "upload",
+			LocalPath:  filepath.Join(localDir, filepath.FromSlash(name)),
+			RemotePath: remotePath,
+			Size:       file.Size,
+		}
+
+		switch {
+		case !found:
+			action.Reason = "new"
+		case existing.Type != "file":
+			return nil, fmt.Errorf("%s is a directory on the controller but a file locally", remotePath)
+		case !sizeMatches(file.Size, existing):
+			action.Reason = fmt.Sprintf("size changed (%d -> %d bytes)", existing.Size, file.Size)
+		case !existing.Modified.IsZero() && file.Modified.Truncate(time.Second).After(existing.Modified):
+			action.Reason = "modified locally"
+		case opts.Checksum || (existing.SizeRounding > 0 && existing.Modified.IsZero()):
+			changed, err := c.contentChanged(ctx, action.LocalPath, remotePath, sd)
+			switch {
+			case errors.Is(err, ErrDownloadUnsupported) && !opts.Checksum:
+				action.Reason = "size only listed approximately"
+			case err != nil:
+				return nil, err
+			case changed:
+				action.Reason = "checksum changed"
+			default:
+				action.Action = "skip"
+				action.Reason = "checksum matches"
+			}
+		default:
+			action.Action = "skip"
+			action.Reason = "unchanged"
+		}
+		uploads = append(uploads, action)
+	}
+
+	if opts.Delete {
+		var extras []string
+		for name := range remote {
+			if _, found := local[name]; !found {
+				extras = append(extras, name)
+			}
+		}
+		sort.Strings(extras)
+
+		var removedDirs []string
+		for _, name := range extras {
+			// Contents of a removed directory go with it
+			covered := false
+			for _, dir := range removedDirs {
+				if strings.HasPrefix(name, dir+"/") {
+					covered = true
+					break
+				}
+			}
+			if covered {
+				continue
+			}
+
+			remotePath := path.Join(remoteDir, name)
+			if remote[name].Type == "directory" {
+				removedDirs = append(removedDirs, name)
+				rmdirs = append(rmdirs, SyncAction{Action: "rmdir", RemotePath: remotePath, Reason: "not present locally"})
+			} else {
+				deletes = append(deletes, SyncAction{Action: "delete", RemotePath: remotePath, Size: remote[name].Size, Reason: "not present locally"})
+			}
+		}
+	}
+
+	plan.Actions = append(plan.Actions, mkdirs...)
+	plan.Actions = append(plan.Actions, uploads...)
+	plan.Actions = append(plan.Actions, deletes...)
+	plan.Actions = append(plan.Actions, rmdirs...)
+
+	return plan, nil
+}
+
+// contentChanged reports whether a local file's SHA256 differs from the copy on the controller
+func (c *Client) contentChanged(ctx context.Context, localPath, remotePath string, sd bool) (bool, error) {
+	local, err := fileSHA256(localPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to checksum %s: %w", localPath, err)
+	}
+
+	remote, err := c.remoteSHA256(ctx, remotePath, sd)
+	if err != nil {
+		return false, fmt.Errorf("failed to checksum %s on controller: %w", remotePath, err)
+	}
+	return local != remote, nil
+}
+
+// ApplySync executes a sync plan, reporting upload progress to onProgress
+func (c *Client) ApplySync(ctx context.Context, plan *SyncPlan, onProgress func(*TransferProgress)) error {
+	for _, action := range plan.Actions {
+		var err error
+		switch action.Action {
+		case "mkdir":
+			err = c.MakeDir(ctx, action.RemotePath, plan.SD)
+		case "upload":
+			err = c.UploadFileWithProgress(ctx, action.LocalPath, action.RemotePath, fileEndpoint(plan.SD), onProgress)
+		case "delete":
+			err = c.DeleteFile(ctx, action.RemotePath, plan.SD)
+		case "rmdir":
+			err = c.RemoveDir(ctx, action.RemotePath, plan.SD)
+		}
+
+		if err != nil {
+			return fmt.Errorf("sync %s %s: %w", action.Action, action.RemotePath, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package fluidnc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Telnet protocol bytes used when stripping option negotiation
+const (
+	telnetIAC  = 0xFF
+	telnetSB   = 0xFA
+	telnetSE   = 0xF0
+	telnetWILL = 0xFB
+	telnetDONT = 0xFE
+)
+
+// streamTransport carries commands over a byte stream such as a TCP socket.
+// Messages are assembled from whole lines that arrived together.
+type streamTransport struct {
+	rw       io.ReadWriteCloser
+	reader   *bufio.Reader
+	deadline func(time.Time) error
+	filter   func([]byte) []byte
+	partial  []byte // Start of a line whose read was interrupted
+}
+
+// newStreamTransport wraps a stream; deadline and filter may be nil
+func newStreamTransport(rw io.ReadWriteCloser, deadline func(time.Time) error, filter func([]byte) []byte) *streamTransport {
+	return &streamTransport{
+		rw:       rw,
+		reader:   bufio.NewReader(rw),
+		deadline: deadline,
+		filter:   filter,
+	}
+}
+
+// Write sends raw bytes
+func (t *streamTransport) Write(data []byte) error {
+	_, err := t.rw.Write(data)
+	return err
+}
+
+// readLine reads one line, applying the filter and skipping lines that are
+// empty after filtering. When a deadline or cancellation interrupts a line,
+// the part received is kept and completed by the next call.
+func (t *streamTransport) readLine() (string, error) {
+	for {
+		line, err := t.reader.ReadBytes('\n')
+		if err != nil {
+			t.partial = append(t.partial, line...)
+			return "", err
+		}
+		if len(t.partial) > 0 {
+			line = append(t.partial, line...)
+			t.partial = nil
+		}
+		if t.filter != nil {
+			line = t.filter(line)
+		}
+		if text := strings.TrimRight(string(line), "\r\n"); strings.TrimSpace(text) != "" {
+			return text, nil
+		}
+	}
+}
+
+// ReadMessage returns the next line plus any further complete lines already buffered
+func (t *streamTransport) ReadMessage() (string, error) {
+	first, err := t.readLine()
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{first}
+	for t.reader.Buffered() > 0 {
+		buffered, _ := t.reader.Peek(t.reader.Buffered())
+		if !bytes.Contains(buffered, []byte{'\n'}) {
+			break
+		}
+		line, err := t.readLine()
+		if err != nil {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// SetReadDeadline sets the read deadline if the stream supports one
+func (t *streamTransport) SetReadDeadline(deadline time.Time) error {
+	if t.deadline == nil {
+		return nil
+	}
+	return t.deadline(deadline)
+}
+
+// Close closes the stream
+func (t *streamTransport) Close() error {
+	return t.rw.Close()
+}
+
+// dialTelnet connects to the FluidNC Telnet server
+func dialTelnet(ctx context.Context, host string, port int, timeout time.Duration) (Transport, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Telnet: %w", err)
+	}
+
+	return newStreamTransport(conn, conn.SetReadDeadline, stripTelnetNegotiation), nil
+}
+
+// stripTelnetNegotiation removes IAC option negotiation sequences from received data
+func stripTelnetNegotiation(data []byte) []byte {
+	if bytes.IndexByte(data, telnetIAC) < 0 {
+		return data
+	}
+
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != telnetIAC || i+1 >= len(data) {
+			out = append(out, data[i])
+			continue
+		}
+
+		switch cmd := data[i+1]; {
+		case cmd == telnetIAC:
+			// Escaped 0xFF data byte
+			out = append(out, telnetIAC)
+			i++
+		case cmd == telnetSB:
+			// Skip subnegotiation up to IAC SE
+			end := bytes.Index(data[i:], []byte{telnetIAC, telnetSE})
+			if end < 0 {
+				return out
+			}
+			i += end + 1
+		case cmd >= telnetWILL && cmd <= telnetDONT:
+			i += 2
+		default:
+			i++
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,34 @@
+package fluidnc
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStreamTransportKeepsInterruptedLine(t *testing.T) {
+	client, controller := net.Pipe()
+	defer controller.Close()
+	transport := newStreamTransport(client, client.SetReadDeadline, stripTelnetNegotiation)
+	defer transport.Close()
+
+	go controller.Write([]byte("<Idle|MPos:1.000,"))
+
+	transport.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if _, err := transport.ReadMessage(); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("ReadMessage error = %v, want deadline exceeded", err)
+	}
+	transport.SetReadDeadline(time.Time{})
+
+	go controller.Write([]byte("2.000,3.000>\nok\n"))
+
+	message, err := transport.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if want := "<Idle|MPos:1.000,2.000,3.000>\nok"; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+}
@@ -0,0 +1,155 @@
+package fluidnc
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// traceBodyLimit is how much of each HTTP body is recorded in a protocol trace
+const traceBodyLimit = 4096
+
+// tracedTransport records every message sent and received on a Transport
+type tracedTransport struct {
+	Transport
+	name   string
+	logger *slog.Logger
+}
+
+// Write sends data and records it
+func (t *tracedTransport) Write(data []byte) error {
+	err := t.Transport.Write(data)
+	if err != nil {
+		t.logger.Info("send failed", "transport", t.name, "data", string(data), "error", err)
+	} else {
+		t.logger.Info("send", "transport", t.name, "data", string(data))
+	}
+	return err
+}
+
+// ReadMessage receives a message and records it
+func (t *tracedTransport) ReadMessage() (string, error) {
+	message, err := t.Transport.ReadMessage()
+	if err != nil {
+		t.logger.Info("receive failed", "transport", t.name, "error", err)
+	} else {
+		t.logger.Info("receive", "transport", t.name, "data", message)
+	}
+	return message, err
+}
+
+// Close closes the connection and records it
+func (t *tracedTransport) Close() error {
+	t.logger.Info("disconnect", "transport", t.name)
+	return t.Transport.Close()
+}
+
+// tracedRoundTripper records every HTTP request and response
+type tracedRoundTripper struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+// tracedHTTPClient returns a copy of client whose requests are recorded to logger
+func tracedHTTPClient(client *http.Client, logger *slog.Logger) *http.Client {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	traced := *client
+	traced.Transport = &tracedRoundTripper{next: next, logger: logger}
+	return &traced
+}
+
+// RoundTrip performs the request, recording it and the response
+func (t *tracedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []any{"method", req.Method, "url", req.URL.Redacted()}
+	if req.ContentLength > 0 {
+		attrs = append(attrs, "length", req.ContentLength, "content_type", req.Header.Get("Content-Type"))
+	}
+	if body, ok := traceRequestBody(req); ok {
+		attrs = append(attrs, "body", body)
+	}
+	t.logger.Info("http request", attrs...)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.Info("http failed", "method", req.Method, "url", req.URL.Redacted(), "elapsed", time.Since(start), "error", err)
+		return nil, err
+	}
+
+	attrs = []any{"method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "elapsed", time.Since(start)}
+
+	// Record the start of the body without consuming it for the caller
+	peek, _ := io.ReadAll(io.LimitReader(resp.Body, traceBodyLimit+1))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+	if len(peek) > 0 {
+		attrs = append(attrs, "body", traceText(peek))
+	}
+
+	t.logger.Info("http response", attrs...)
+	return resp, nil
+}
+
+// traceRequestBody returns a form request's body with the password masked.
+// Uploads and other bodies are only recorded by length.
+func traceRequestBody(req *http.Request) (string, bool) {
+	if req.GetBody == nil || req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		return "", false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, traceBodyLimit))
+	if err != nil {
+		return "", false
+	}
+
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return "", false
+	}
+	for key := range form {
+		if strings.EqualFold(key, "password") {
+			form.Set(key, "REDACTED")
+		}
+	}
+	return form.Encode(), true
+}
+
+// traceText formats a recorded body, truncating it and summarising binary data
+func traceText(data []byte) string {
+	truncated := len(data) > traceBodyLimit
+	if truncated {
+		data = data[:traceBodyLimit]
+	}
+
+	checked := data
+	if truncated {
+		// The limit may fall inside a multi-byte character
+		checked = data[:len(data)-utf8.UTFMax]
+	}
+	if !utf8.Valid(checked) {
+		return "(binary data)"
+	}
+
+	text := strings.ToValidUTF8(string(data), "")
+	if truncated {
+		text += "...(truncated)"
+	}
+	return text
+}
@@ -0,0 +1,145 @@
+package fluidnc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrDownloadUnsupported is returned by DownloadFile when the controller
+// does not serve stored files back
+var ErrDownloadUnsupported = errors.New("controller does not support downloading files")
+
+// transferReportInterval limits how often transfer progress callbacks fire
+const transferReportInterval = 250 * time.Millisecond
+
+// transferCounter is an io.Writer that counts bytes and reports transfer progress
+type transferCounter struct {
+	name     string
+	total    int64
+	bytes    int64
+	started  time.Time
+	reported time.Time
+	callback func(*TransferProgress)
+}
+
+// newTransferCounter creates a counter for a transfer of total bytes (0 if unknown)
+func newTransferCounter(name string, total int64, callback func(*TransferProgress)) *transferCounter {
+	return &transferCounter{
+		name:     name,
+		total:    total,
+		started:  time.Now(),
+		callback: callback,
+	}
+}
+
+// Write counts transferred bytes and reports progress at most every transferReportInterval
+func (t *transferCounter) Write(p []byte) (int, error) {
+	t.bytes += int64(len(p))
+	if t.callback != nil && time.Since(t.reported) >= transferReportInterval {
+		t.reported = time.Now()
+		t.callback(t.progress(false))
+	}
+	return len(p), nil
+}
+
+// finish reports the final transfer progress
+func (t *transferCounter) finish() {
+	if t.callback != nil {
+		t.callback(t.progress(true))
+	}
+}
+
+// progress builds a progress snapshot with rate and remaining time
+func (t *transferCounter) progress(done bool) *TransferProgress {
+	p := &TransferProgress{
+		Name:    t.name,
+		Bytes:   t.bytes,
+		Total:   t.total,
+		Elapsed: time.Since(t.started),
+		Done:    done,
+	}
+
+	if seconds := p.Elapsed.Seconds(); seconds > 0 {
+		p.Rate = float64(p.Bytes) / seconds
+	}
+	if p.Rate > 0 && p.Total > p.Bytes {
+		p.Remaining = time.Duration(float64(p.Total-p.Bytes) / p.Rate * float64(time.Second))
+	}
+
+	return p
+}
+
+// remoteFileURL builds the URL serving a stored file, resolved by remoteStoragePath
+func (c *Client) remoteFileURL(remotePath string) string {
+	sd, filePath := remoteStoragePath(remotePath)
+	if sd {
+		filePath = "/sd" + filePath
+	} else {
+		filePath = "/localfs" + filePath
+	}
+
+	u := url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s:%d", c.config.Host, c.config.Port),
+		Path:   filePath,
+	}
+	return u.String()
+}
+
+// DownloadFile streams a file stored on the controller to w. remotePath is
+// prefixed with /sd/ or /localfs/; without one it is on the local filesystem.
+func (c *Client) DownloadFile(ctx context.Context, remotePath string, w io.Writer) error {
+	return c.DownloadFileWithProgress(ctx, remotePath, w, nil)
+}
+
+// DownloadFileWithProgress streams a file stored on the controller to w,
+// reporting transfer progress to onProgress
+func (c *Client) DownloadFileWithProgress(ctx context.Context, remotePath string, w io.Writer, onProgress func(*TransferProgress)) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.remoteFileURL(remotePath), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Large files can take longer than the request timeout, so rely on ctx instead
+	client := *c.client
+	client.Timeout = 0
+
+	resp, err := c.doWith(&client, req)
+	if err != nil {
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("file not found: %s", remotePath)
+	}
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		return fmt.Errorf("%w (status %d)", ErrDownloadUnsupported, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("download failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	total := resp.ContentLength
+	if total < 0 {
+		total = 0
+	}
+
+	counter := newTransferCounter(remotePath, total, onProgress)
+	if _, err := io.Copy(io.MultiWriter(w, counter), resp.Body); err != nil {
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+	counter.finish()
+
+	if total > 0 && counter.bytes != total {
+		return fmt.Errorf("download truncated: received %d of %d bytes", counter.bytes, total)
+	}
+
+	return nil
+}
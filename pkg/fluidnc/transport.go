@@ -0,0 +1,147 @@
+package fluidnc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Transport is a connection carrying FluidNC's line based command protocol
+type Transport interface {
+	// Write sends raw bytes to the controller
+	Write(data []byte) error
+	// ReadMessage returns the next block of text received, which may hold several lines
+	ReadMessage() (string, error)
+	// SetReadDeadline bounds pending and future reads; a zero time disables the deadline
+	SetReadDeadline(t time.Time) error
+	// Close closes the connection
+	Close() error
+}
+
+// dialTransport opens the transport selected by the configuration, recording
+// its traffic when a protocol trace is enabled
+func (c *Client) dialTransport(ctx context.Context) (Transport, error) {
+	if c.trace == nil {
+		return c.dialConfigured(ctx)
+	}
+
+	name := c.config.Transport
+	if name == "" {
+		name = "ws"
+	}
+
+	start := time.Now()
+	conn, err := c.dialConfigured(ctx)
+	if err != nil {
+		c.trace.Info("connect failed", "transport", name, "elapsed", time.Since(start), "error", err)
+		return nil, err
+	}
+	c.trace.Info("connect", "transport", name, "elapsed", time.Since(start))
+
+	return &tracedTransport{Transport: conn, name: name, logger: c.trace}, nil
+}
+
+// dialConfigured opens the transport selected by the configuration
+func (c *Client) dialConfigured(ctx context.Context) (Transport, error) {
+	switch c.config.Transport {
+	case "", "ws", "websocket":
+		return c.dialWebSocket(ctx)
+	case "telnet", "tcp":
+		return dialTelnet(ctx, c.config.Host, c.config.TelnetPort, c.config.Timeout)
+	case "serial":
+		return dialSerial(ctx, c.config.SerialPort, c.config.Baud, c.config.SerialReset, c.config.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown transport %q (expected ws, telnet or serial)", c.config.Transport)
+	}
+}
+
+// readMessage reads the next message from conn, giving up when ctx is done.
+// A deadline on ctx becomes the read deadline; cancellation interrupts the read.
+func readMessage(ctx context.Context, conn Transport) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return "", err
+		}
+		defer conn.SetReadDeadline(time.Time{})
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetReadDeadline(time.Now())
+	})
+	defer func() {
+		if !stop() {
+			// The read was interrupted; clear the deadline for later reads
+			conn.SetReadDeadline(time.Time{})
+		}
+	}()
+
+	message, err := conn.ReadMessage()
+	if err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return message, err
+}
+
+// wsTransport carries commands over the WebUI WebSocket
+type wsTransport struct {
+	conn *websocket.Conn
+}
+
+// dialWebSocket connects to the WebSocket server, sharing the HTTP session cookies
+func (c *Client) dialWebSocket(ctx context.Context) (Transport, error) {
+	wsURL := url.URL{
+		Scheme: "ws",
+		Host:   fmt.Sprintf("%s:%d", c.config.Host, c.config.WebSocketPort),
+		Path:   "/",
+	}
+
+	if err := c.ensureLogin(ctx); err != nil {
+		return nil, err
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.Jar = c.client.Jar
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL.String(), nil)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusUnauthorized && c.hasCredentials() {
+		// The session may have expired; log in again and retry once
+		if loginErr := c.Login(ctx); loginErr != nil {
+			return nil, loginErr
+		}
+		conn, _, err = dialer.DialContext(ctx, wsURL.String(), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
+	}
+
+	return &wsTransport{conn: conn}, nil
+}
+
+// Write sends data as a text message
+func (t *wsTransport) Write(data []byte) error {
+	return t.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// ReadMessage returns the next WebSocket message
+func (t *wsTransport) ReadMessage() (string, error) {
+	_, message, err := t.conn.ReadMessage()
+	return string(message), err
+}
+
+// SetReadDeadline sets the read deadline on the underlying connection
+func (t *wsTransport) SetReadDeadline(deadline time.Time) error {
+	return t.conn.SetReadDeadline(deadline)
+}
+
+// Close closes the WebSocket connection
+func (t *wsTransport) Close() error {
+	return t.conn.Close()
+}
@@ -0,0 +1,281 @@
+package fluidnc
+
+import "time"
+
+// Config represents the application configuration
+type Config struct {
+	Host           string        `yaml:"host" mapstructure:"host"`
+	Port           int           `yaml:"port" mapstructure:"port"`
+	WebSocketPort  int           `yaml:"websocket_port" mapstructure:"websocket_port"`
+	Transport      string        `yaml:"transport" mapstructure:"transport"` // "ws", "telnet" or "serial"
+	TelnetPort     int           `yaml:"telnet_port" mapstructure:"telnet_port"`
+	SerialPort     string        `yaml:"serial_port" mapstructure:"serial_port"`
+	Baud           int           `yaml:"baud" mapstructure:"baud"`
+	SerialReset    bool          `yaml:"serial_reset" mapstructure:"serial_reset"`
+	Username       string        `yaml:"username" mapstructure:"username"`
+	Password       string        `yaml:"password" mapstructure:"password"`
+	Machine        string        `yaml:"machine" mapstructure:"machine"` // Active machine profile, if any
+	Axes           string        `yaml:"axes" mapstructure:"axes"`       // Axis letters reported by the machine, e.g. "XYZ" or "XYZA"
+	Timeout        time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	RetryAttempts  int           `yaml:"retry_attempts" mapstructure:"retry_attempts"`
+	RetryDelay     time.Duration `yaml:"retry_delay" mapstructure:"retry_delay"`
+	OutputFormat   string        `yaml:"output_format" mapstructure:"output_format"`     // "text", "json", "jsonl", "csv" or "yaml"
+	OutputTemplate string        `yaml:"output_template" mapstructure:"output_template"` // Go template applied to each record; overrides OutputFormat
+	Verbose        bool          `yaml:"verbose" mapstructure:"verbose"`
+	LogLevel       string        `yaml:"log_level" mapstructure:"log_level"`   // "debug", "info", "warn" or "error"
+	LogFormat      string        `yaml:"log_format" mapstructure:"log_format"` // "text" or "json"
+	TraceFile      string        `yaml:"trace_file" mapstructure:"trace_file"` // Protocol trace destination; tracing is off when empty
+	StatusInterval time.Duration `yaml:"status_interval" mapstructure:"status_interval"`
+	ActiveInterval time.Duration `yaml:"active_interval" mapstructure:"active_interval"` // Status interval while running, jogging or homing; zero uses StatusInterval
+	CommandDelay   time.Duration `yaml:"command_delay" mapstructure:"command_delay"`
+}
+
+// DefaultConfig returns the configuration used when no settings are given
+func DefaultConfig() *Config {
+	return &Config{
+		Host:           "192.168.1.100",
+		Port:           80,
+		WebSocketPort:  81,
+		Transport:      "ws",
+		TelnetPort:     23,
+		Baud:           115200,
+		Axes:           "XYZ",
+		Timeout:        30 * time.Second,
+		RetryAttempts:  3,
+		RetryDelay:     time.Second,
+		OutputFormat:   "text",
+		LogLevel:       "warn",
+		LogFormat:      "text",
+		StatusInterval: time.Second,
+		ActiveInterval: 200 * time.Millisecond,
+		CommandDelay:   100 * time.Millisecond,
+	}
+}
+
+// FluidNCStatus represents parsed status from FluidNC
+type FluidNCStatus struct {
+	State        string    `json:"state"`
+	MachinePos   Position  `json:"machine_position"`
+	WorkPos      Position  `json:"work_position"`
+	FeedRate     int       `json:"feed_rate"`
+	SpindleSpeed int       `json:"spindle_speed"`
+	Overrides    Overrides `json:"overrides"`
+	Pins         string    `json:"pins"`
+	Buffer       Buffer    `json:"buffer"`
+	LineNumber   int       `json:"line_number"`
+	SDProgress   float64   `json:"sd_progress,omitempty"`
+	SDFile       string    `json:"sd_file,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	Raw          string    `json:"raw_response"`
+}
+
+// Position represents X, Y, Z and optional rotary A, B, C coordinates
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+	A float64 `json:"a,omitempty"`
+	B float64 `json:"b,omitempty"`
+	C float64 `json:"c,omitempty"`
+}
+
+// Axis returns the coordinate for an axis letter
+func (p Position) Axis(letter rune) float64 {
+	switch letter {
+	case 'X', 'x':
+		return p.X
+	case 'Y', 'y':
+		return p.Y
+	case 'Z', 'z':
+		return p.Z
+	case 'A', 'a':
+		return p.A
+	case 'B', 'b':
+		return p.B
+	case 'C', 'c':
+		return p.C
+	}
+	return 0
+}
+
+// Overrides represents feed/rapid/spindle overrides
+type Overrides struct {
+	Feed    int `json:"feed"`
+	Rapid   int `json:"rapid"`
+	Spindle int `json:"spindle"`
+}
+
+// Buffer represents planner and serial buffer info
+type Buffer struct {
+	Planner int `json:"planner"`
+	Serial  int `json:"serial"`
+}
+
+// AlarmInfo represents alarm information
+type AlarmInfo struct {
+	Code        int       `json:"code"`
+	Description string    `json:"description"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// FileInfo represents file information from FluidNC
+type FileInfo struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Type     string    `json:"type"`               // "file" or "directory"
+	Modified time.Time `json:"modified,omitempty"` // zero when the controller has no clock
+
+	SizeRounding int64 `json:"size_rounding,omitempty"` // How far Size may be off when listed rounded, e.g. "1.23 KB"; zero when exact
+}
+
+// FileListResponse represents the response from listing files
+type FileListResponse struct {
+	Files      []FileInfo `json:"files"`
+	Path       string     `json:"path"`
+	TotalSpace int64      `json:"total_space"` // 0 when not reported
+	UsedSpace  int64      `json:"used_space"`
+	Occupation int        `json:"occupation"` // percent of capacity used
+}
+
+// FreeSpace returns the unused capacity of the listed filesystem
+func (r *FileListResponse) FreeSpace() int64 {
+	if r.TotalSpace <= r.UsedSpace {
+		return 0
+	}
+	return r.TotalSpace - r.UsedSpace
+}
+
+// RunOptions configures G-code file execution
+type RunOptions struct {
+	Name       string               // Job name reported in the summary
+	Monitor    bool                 // Poll machine status while streaming
+	OnProgress func(*JobProgress)   // Called after each acknowledged line and status update
+	OnMessage  func(string)         // Called for [MSG:] pushes during on-device runs
+	OnStatus   func(*FluidNCStatus) // Called with each status sample while monitoring
+}
+
+// JobProgress represents the progress of a streamed G-code job
+type JobProgress struct {
+	TotalLines     int           `json:"total_lines"`
+	LinesSent      int           `json:"lines_sent"`
+	LinesAcked     int           `json:"lines_acked"`
+	TotalBytes     int64         `json:"total_bytes"`
+	BytesSent      int64         `json:"bytes_sent"`
+	PercentBytes   float64       `json:"percent_bytes"`
+	PercentTime    float64       `json:"percent_time"`
+	EstimatedTotal time.Duration `json:"estimated_total"`
+	EstimatedDone  time.Duration `json:"estimated_done"`
+	Elapsed        time.Duration `json:"elapsed"`
+	Remaining      time.Duration `json:"remaining"`
+	CurrentFeed    int           `json:"current_feed"`
+	ProgrammedFeed float64       `json:"programmed_feed"`
+	State          string        `json:"state"`
+}
+
+// JobSummary represents the outcome of a streamed G-code job
+type JobSummary struct {
+	File           string        `json:"file"`
+	Completed      bool          `json:"completed"`
+	LinesSent      int           `json:"lines_sent"`
+	LinesAcked     int           `json:"lines_acked"`
+	TotalLines     int           `json:"total_lines"`
+	BytesSent      int64         `json:"bytes_sent"`
+	TotalBytes     int64         `json:"total_bytes"`
+	Elapsed        time.Duration `json:"elapsed"`
+	EstimatedTotal time.Duration `json:"estimated_total"`
+}
+
+// TransferProgress represents progress of a file upload or download
+type TransferProgress struct {
+	Name      string        `json:"name"`
+	Bytes     int64         `json:"bytes"`
+	Total     int64         `json:"total"` // 0 when the size is unknown
+	Rate      float64       `json:"rate"`  // bytes per second
+	Elapsed   time.Duration `json:"elapsed"`
+	Remaining time.Duration `json:"remaining"`
+	Done      bool          `json:"done"`
+}
+
+// VerifyResult represents the outcome of verifying an uploaded file
+type VerifyResult struct {
+	Path             string `json:"path"`
+	LocalSize        int64  `json:"local_size"`
+	RemoteSize       int64  `json:"remote_size"`
+	SizeVerified     bool   `json:"size_verified"`
+	LocalSHA256      string `json:"local_sha256"`
+	RemoteSHA256     string `json:"remote_sha256,omitempty"`
+	ChecksumVerified bool   `json:"checksum_verified"`
+}
+
+// SyncAction represents a single step of a directory sync plan
+type SyncAction struct {
+	Action     string `json:"action"` // "mkdir", "upload", "delete", "rmdir" or "skip"
+	LocalPath  string `json:"local_path,omitempty"`
+	RemotePath string `json:"remote_path"`
+	Size       int64  `json:"size,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// SyncOptions configures PlanSync
+type SyncOptions struct {
+	Delete   bool // Schedule remote files missing locally for deletion
+	Checksum bool // Download every file whose size matches and compare SHA256 checksums
+}
+
+// SyncPlan represents the actions needed to mirror a local directory to the controller
+type SyncPlan struct {
+	LocalDir  string       `json:"local_dir"`
+	RemoteDir string       `json:"remote_dir"`
+	SD        bool         `json:"sd"`
+	Actions   []SyncAction `json:"actions"`
+}
+
+// FirmwareUpdateOptions configures UpdateFirmwareAndVerify
+type FirmwareUpdateOptions struct {
+	ExpectedVersion  string                  // Version the device must report afterwards; empty accepts any change
+	AllowSameVersion bool                    // Accept reflashing the version already installed
+	BackupPath       string                  // Local path for a config backup; empty skips the backup
+	RestartTimeout   time.Duration           // How long to wait for the device to come back; zero waits indefinitely
+	OnProgress       func(*TransferProgress) // Called with upload progress
+	OnStage          func(string)            // Called as each step of the update starts
+}
+
+// FirmwareUpdateResult represents the outcome of a firmware update
+type FirmwareUpdateResult struct {
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+	BackupPath string `json:"backup_path,omitempty"`
+}
+
+// FirmwareValidationOptions configures ValidateFirmwareImage
+type FirmwareValidationOptions struct {
+	MaxSize      int64  // OTA partition size; zero uses DefaultOTAPartitionSize
+	SHA256       string // Expected image checksum
+	ManifestPath string // sha256sum style or JSON release manifest to look the checksum up in
+}
+
+// FirmwareImageInfo describes a validated ESP32 firmware image
+type FirmwareImageInfo struct {
+	Size             int64  `json:"size"`
+	Segments         int    `json:"segments"`
+	ChipID           uint16 `json:"chip_id"`
+	ChipName         string `json:"chip_name"`
+	HashAppended     bool   `json:"hash_appended"`
+	SHA256           string `json:"sha256"`
+	ChecksumVerified bool   `json:"checksum_verified"`
+}
+
+// DiscoveredDevice represents a FluidNC device found on the local network
+type DiscoveredDevice struct {
+	Name           string `json:"name"`
+	Hostname       string `json:"hostname,omitempty"`
+	Host           string `json:"host"`
+	Port           int    `json:"port"`
+	WebSocketPort  int    `json:"websocket_port"`
+	TelnetPort     int    `json:"telnet_port,omitempty"`
+	Version        string `json:"version,omitempty"`
+	Model          string `json:"model,omitempty"`
+	Manufacturer   string `json:"manufacturer,omitempty"`
+	DescriptionURL string `json:"description_url"`
+	Source         string `json:"source"`
+}
@@ -0,0 +1,118 @@
+package fluidnc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// sizeMatches compares a local size against a listed file. Exact byte counts
+// must be equal; rounded sizes such as "1.23 KB" may differ by their rounding.
+func sizeMatches(local int64, remote FileInfo) bool {
+	diff := local - remote.Size
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= remote.SizeRounding
+}
+
+// fileSHA256 returns the hex encoded SHA256 of a local file
+func fileSHA256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// remoteSHA256 downloads a file stored on the controller and returns its hex
+// encoded SHA256
+func (c *Client) remoteSHA256(ctx context.Context, remotePath string, sd bool) (string, error) {
+	prefix := "/localfs"
+	if sd {
+		prefix = "/sd"
+	}
+
+	hash := sha256.New()
+	if err := c.DownloadFile(ctx, prefix+"/"+strings.TrimPrefix(remotePath, "/"), hash); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// VerifyUpload checks that an uploaded file arrived intact. The remote listing
+// size is always compared; the file is then downloaded and its checksum
+// compared. A mismatch or failed download is reported as an error; only a
+// controller that does not serve files back skips the checksum.
+func (c *Client) VerifyUpload(ctx context.Context, filePath, destination string, sd bool) (*VerifyResult, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	name := uploadName(filePath, destination)
+	dir, base := splitRemotePath(name)
+	result := &VerifyResult{Path: name, LocalSize: info.Size(), RemoteSize: -1}
+
+	listing, err := c.ListFiles(ctx, dir, sd)
+	if err != nil {
+		return result, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	var remote FileInfo
+	for _, file := range listing.Files {
+		if file.Name == base && file.Type == "file" {
+			remote = file
+			result.RemoteSize = file.Size
+			break
+		}
+	}
+
+	if result.RemoteSize < 0 {
+		return result, fmt.Errorf("verification failed: %s not found on %s", name, StorageLabel(sd))
+	}
+	if !sizeMatches(result.LocalSize, remote) {
+		return result, fmt.Errorf("verification failed: %s is %d bytes on controller, expected %d", name, result.RemoteSize, result.LocalSize)
+	}
+	result.SizeVerified = true
+
+	result.LocalSHA256, err = fileSHA256(filePath)
+	if err != nil {
+		return result, fmt.Errorf("failed to checksum file: %w", err)
+	}
+
+	result.RemoteSHA256, err = c.remoteSHA256(ctx, name, sd)
+	if err != nil {
+		if errors.Is(err, ErrDownloadUnsupported) {
+			// Not every firmware build serves stored files back; the size check stands
+			c.logger.Warn("checksum verification skipped", "path", name, "error", err)
+			return result, nil
+		}
+		return result, fmt.Errorf("verification failed: could not read back %s: %w", name, err)
+	}
+
+	if result.RemoteSHA256 != result.LocalSHA256 {
+		return result, fmt.Errorf("verification failed: %s checksum %s does not match local %s", name, result.RemoteSHA256, result.LocalSHA256)
+	}
+	result.ChecksumVerified = true
+
+	return result, nil
+}
+
+// StorageLabel returns a human readable name for the targeted filesystem
+func StorageLabel(sd bool) string {
+	if sd {
+		return "SD card"
+	}
+	return "local filesystem"
+}